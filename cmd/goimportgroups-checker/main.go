@@ -0,0 +1,49 @@
+// Command goimportgroups-checker runs the goimportgroups analyzer alone on packages, with the standard flags of the
+// analysis drivers, like -json to write the diagnostics as JSON and -fix to apply the suggested fixes:
+//
+//	goimportgroups-checker -groups 'fmt:os;.*' -fix ./...
+package main
+
+import (
+	"flag"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+func main() {
+	singlechecker.Main(newAnalyzer())
+}
+
+// newAnalyzer returns the analyzer with its flags but -v, which the driver defines too and panics on flags defined
+// twice. The -v of the driver, kept without effect for compatibility, sets the one of the analyzer instead.
+func newAnalyzer() *analysis.Analyzer {
+	a := analyzer.NewAnalyzer()
+	verbose := a.Flags.Lookup("v").Value
+
+	var flags flag.FlagSet
+	a.Flags.VisitAll(func(f *flag.Flag) {
+		if f.Name != "v" {
+			flags.Var(f.Value, f.Name, f.Usage)
+		}
+	})
+	a.Flags = flags
+
+	// the flags of the driver are parsed once the analyzer runs
+	var once sync.Once
+	run := a.Run
+	a.Run = func(pass *analysis.Pass) (interface{}, error) {
+		once.Do(func() {
+			if f := flag.Lookup("v"); f != nil && f.Value.String() == "true" {
+				_ = verbose.Set("true")
+			}
+		})
+
+		return run(pass)
+	}
+
+	return a
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChecker(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not found")
+	}
+
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "goimportgroups-checker")
+	if out, err := exec.Command("go", "build", "-o", bin, ".").CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v: %s", err, out)
+	}
+
+	module := filepath.Join(dir, "module")
+	if err := os.Mkdir(module, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, src := range map[string]string{
+		"go.mod":  "module example.com/module\n\ngo 1.21\n",
+		"main.go": "package main\n\nimport (\n\t\"time\"\n\n\t\"fmt\"\n)\n\nvar _, _ = time.Now, fmt.Println\n",
+	} {
+		if err := os.WriteFile(filepath.Join(module, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(bin, "-groups", "fmt;time", "-v", "./...")
+	cmd.Dir, cmd.Stdout, cmd.Stderr = module, &stdout, &stderr
+	if err := cmd.Run(); err == nil {
+		t.Fatalf("expected the issue to fail the run, got\n%s%s", stdout.String(), stderr.String())
+	}
+
+	if !strings.Contains(stderr.String(), `main.go:6:2: import "fmt" belongs to group "fmt" (group 1)`) {
+		t.Errorf("expected the issue of fmt, got\n%s", stderr.String())
+	}
+
+	// -v is the one of the driver, setting the one of the analyzer
+	if !strings.Contains(stderr.String(), `msg="resolved configuration" package=example.com/module groups=fmt;time`) {
+		t.Errorf("expected -v to log the resolved configuration, got\n%s", stderr.String())
+	}
+}
@@ -0,0 +1,17 @@
+// Command goimportgroups-vet runs the goimportgroups analyzer as a tool of go vet, to benefit from its caching of the
+// results of the packages left unchanged:
+//
+//	go vet -vettool=$(which goimportgroups-vet) ./...
+//
+// The flags of the analyzer are the ones of goimportgroups, passed to go vet, like -goimportgroups.groups.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+func main() {
+	unitchecker.Main(analyzer.NewAnalyzer())
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVetTool(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not found")
+	}
+
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "goimportgroups-vet")
+	if out, err := exec.Command("go", "build", "-o", bin, ".").CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v: %s", err, out)
+	}
+
+	module := filepath.Join(dir, "module")
+	if err := os.Mkdir(module, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, src := range map[string]string{
+		"go.mod":  "module example.com/module\n\ngo 1.21\n",
+		"main.go": "package main\n\nimport (\n\t\"time\"\n\n\t\"fmt\"\n)\n\nvar _, _ = time.Now, fmt.Println\n",
+	} {
+		if err := os.WriteFile(filepath.Join(module, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command("go", "vet", "-vettool="+bin, "-goimportgroups.groups=fmt;time", "./...")
+	cmd.Dir = module
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the issue to fail go vet, got\n%s", out)
+	}
+
+	if !strings.Contains(string(out), `main.go:6:2: import "fmt" belongs to group "fmt" (group 1)`) {
+		t.Errorf("expected the issue of fmt, got\n%s", out)
+	}
+}
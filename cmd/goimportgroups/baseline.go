@@ -0,0 +1,167 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+// baseline records the files whose issues are accepted with -baseline write, for -baseline check to report the
+// issues of the other files only. A file is recorded by its path and a hash of its imports, for any change of its
+// imports to report its issues again. The paths are relative to the directory of the baseline file, for a baseline
+// committed along with the code to hold in any checkout and from any working directory.
+type baseline struct {
+	mode   string
+	path   string
+	dir    string
+	hashes map[string]string
+}
+
+// baselineFile is the JSON object written to the baseline file.
+type baselineFile struct {
+	Files []baselineEntry `json:"files"`
+}
+
+// baselineEntry records the file with issues File along with the hash of its imports.
+type baselineEntry struct {
+	File    string `json:"file"`
+	Imports string `json:"imports"`
+}
+
+// newBaseline returns the baseline of the -baseline flag value mode stored in the file path, read from it in check
+// mode, or nil without mode.
+func newBaseline(mode, path string) (*baseline, error) {
+	switch mode {
+	case "":
+		return nil, nil
+	case "write", "check":
+	default:
+		return nil, fmt.Errorf("unknown baseline mode %q, expected write or check", mode)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &baseline{mode: mode, path: path, dir: resolveSymlinks(filepath.Dir(abs)), hashes: map[string]string{}}
+	if mode == "write" {
+		return b, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline: %w", err)
+	}
+
+	var f baselineFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing baseline %s: %w", path, err)
+	}
+
+	for _, e := range f.Files {
+		b.hashes[e.File] = e.Imports
+	}
+
+	return b, nil
+}
+
+// filter returns the issues of the file name not accepted by the baseline, recording them in write mode.
+func (b *baseline) filter(name string, issues []analyzer.Issue) ([]analyzer.Issue, error) {
+	if b == nil || len(issues) == 0 {
+		return issues, nil
+	}
+
+	hash, err := importsHash(name)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := b.key(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.mode == "write" {
+		b.hashes[key] = hash
+		return nil, nil
+	}
+
+	if recorded, ok := b.hashes[key]; ok && recorded == hash {
+		return nil, nil
+	}
+
+	return issues, nil
+}
+
+// key returns the path of the file name relative to the directory of the baseline file, with forward slashes.
+func (b *baseline) key(name string) (string, error) {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(b.dir, resolveSymlinks(abs))
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+// save writes the files recorded in write mode to the baseline file.
+func (b *baseline) save() error {
+	if b == nil || b.mode != "write" {
+		return nil
+	}
+
+	f := baselineFile{Files: []baselineEntry{}}
+	for file, hash := range b.hashes {
+		f.Files = append(f.Files, baselineEntry{File: file, Imports: hash})
+	}
+
+	sort.Slice(f.Files, func(i, j int) bool {
+		return f.Files[i].File < f.Files[j].File
+	})
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(b.path, append(data, '\n'), 0o644)
+}
+
+// importsHash returns the hash of the source of the file name from its first import declaration to the end of its
+// last one.
+func importsHash(name string) (string, error) {
+	src, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, name, src, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+
+	var imports []byte
+	if len(file.Decls) > 0 {
+		start := fset.Position(file.Decls[0].Pos()).Offset
+		end := fset.Position(file.Decls[len(file.Decls)-1].End()).Offset
+		imports = src[start:end]
+	}
+
+	sum := sha256.Sum256(imports)
+
+	return hex.EncodeToString(sum[:]), nil
+}
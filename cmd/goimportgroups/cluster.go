@@ -0,0 +1,257 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+// clusteredLayout returns the layout of the groups of the blocks of imports of sources. The import paths are keyed by
+// the standard library, the local module of module or their first two elements, the keys found in the same block of a
+// file more often than in different ones are clustered into a group, and the groups are ordered by the average
+// position of their keys among the blocks of the files. The group of the most third party keys matches the paths of
+// no other group. It reports false if no group holds third party keys.
+func clusteredLayout(module analyzer.Config, sources []analyzer.NamedSource) (layout, bool) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "std"
+	cfg.LocalModule, cfg.GoVersion = module.LocalModule, module.GoVersion
+	if module.LocalModule != "" {
+		cfg.Groups = "std;localmodule"
+	}
+
+	c, err := analyzer.NewChecker(cfg)
+	if err != nil {
+		return layout{}, false
+	}
+
+	// together and apart count the files pairs of keys share a block of, and the files they are in different blocks
+	// of, and position sums up the indexes of the blocks of the occurrences of the keys, count being their number
+	together, apart := map[[2]string]int{}, map[[2]string]int{}
+	position, count, imports := map[string]float64{}, map[string]int{}, map[string]int{}
+	for _, src := range sources {
+		blocks := importBlocks(c, src)
+		for i, block := range blocks {
+			for key, n := range block {
+				position[key] += float64(i)
+				count[key]++
+				imports[key] += n
+			}
+		}
+
+		for _, pair := range keyPairs(blocks) {
+			if sameBlock(blocks, pair) {
+				together[pair]++
+			} else {
+				apart[pair]++
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(count))
+	for key := range count {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	cluster := map[string]string{}
+	for _, key := range keys {
+		cluster[key] = key
+	}
+
+	var find func(key string) string
+	find = func(key string) string {
+		if cluster[key] != key {
+			cluster[key] = find(cluster[key])
+		}
+
+		return cluster[key]
+	}
+
+	for pair, n := range together {
+		if n > apart[pair] {
+			cluster[find(pair[1])] = find(pair[0])
+		}
+	}
+
+	members := map[string][]string{}
+	var roots []string
+	for _, key := range keys {
+		root := find(key)
+		if members[root] == nil {
+			roots = append(roots, root)
+		}
+
+		members[root] = append(members[root], key)
+	}
+
+	average := func(root string) float64 {
+		sum, n := 0.0, 0
+		for _, key := range members[root] {
+			sum += position[key]
+			n += count[key]
+		}
+
+		return sum / float64(n)
+	}
+
+	sort.SliceStable(roots, func(i, j int) bool {
+		return average(roots[i]) < average(roots[j])
+	})
+
+	catchAll, most := "", 0
+	for _, root := range roots {
+		n := 0
+		for _, key := range members[root] {
+			if key != "std" && key != "localmodule" {
+				n += imports[key]
+			}
+		}
+
+		if n > most {
+			catchAll, most = root, n
+		}
+	}
+
+	if catchAll == "" {
+		return layout{}, false
+	}
+
+	var others []string
+	for _, root := range roots {
+		if root != catchAll {
+			others = append(others, keysPattern(members[root]))
+		}
+	}
+
+	l := layout{groups: make([]configGroup, len(roots))}
+	for i, root := range roots {
+		switch {
+		case root == catchAll && others == nil:
+			l.groups[i] = configGroup{Name: "third-party", Pattern: ".*"}
+		case root == catchAll:
+			l.groups[i] = configGroup{Name: "third-party", Pattern: ".* && !(" + strings.Join(others, " || ") + ")"}
+		case len(members[root]) == 1 && root == "std":
+			l.groups[i] = configGroup{Name: "std", Pattern: "std"}
+		case len(members[root]) == 1 && root == "localmodule":
+			l.groups[i] = configGroup{Name: "local", Pattern: "localmodule"}
+		default:
+			l.groups[i] = configGroup{Name: strings.Join(members[root], ", "), Pattern: keysPattern(members[root])}
+		}
+	}
+
+	return l, true
+}
+
+// importBlocks returns the keys of the imports of each block of imports of src, with their number of imports, the
+// blocks being separated by blank lines or split into several import declarations. The paths of the first group of c
+// are keyed by std, the ones of its second group by localmodule.
+func importBlocks(c *analyzer.Checker, src analyzer.NamedSource) []map[string]int {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, src.Name, src.Src, parser.ImportsOnly)
+	if err != nil {
+		return nil
+	}
+
+	var blocks []map[string]int
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+
+		last := -1
+		for _, spec := range gen.Specs {
+			line := fset.Position(spec.Pos()).Line
+			if last < 0 || line > last+1 {
+				blocks = append(blocks, map[string]int{})
+			}
+
+			last = fset.Position(spec.End()).Line
+
+			path, err := strconv.Unquote(spec.(*ast.ImportSpec).Path.Value)
+			if err != nil {
+				continue
+			}
+
+			blocks[len(blocks)-1][importKey(c, path)]++
+		}
+	}
+
+	return blocks
+}
+
+// importKey returns the key of the import path: std or localmodule for the paths of the first or second group of c,
+// and the first two elements of the path otherwise, like the organization of a repository hosting site.
+func importKey(c *analyzer.Checker, path string) string {
+	switch group, _, _ := c.TraceMatch(path); group {
+	case 1:
+		return "std"
+	case 2:
+		return "localmodule"
+	}
+
+	elems := strings.SplitN(path, "/", 3)
+	if len(elems) > 2 {
+		elems = elems[:2]
+	}
+
+	return strings.Join(elems, "/")
+}
+
+// keyPairs returns the pairs of the distinct keys of blocks, in order.
+func keyPairs(blocks []map[string]int) [][2]string {
+	var keys []string
+	seen := map[string]bool{}
+	for _, block := range blocks {
+		for key := range block {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+
+	var pairs [][2]string
+	for i := range keys {
+		for j := i + 1; j < len(keys); j++ {
+			pairs = append(pairs, [2]string{keys[i], keys[j]})
+		}
+	}
+
+	return pairs
+}
+
+// sameBlock reports whether one of blocks holds both keys of pair.
+func sameBlock(blocks []map[string]int, pair [2]string) bool {
+	for _, block := range blocks {
+		if block[pair[0]] > 0 && block[pair[1]] > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// keysPattern returns the group pattern matching the import paths of keys.
+func keysPattern(keys []string) string {
+	patterns := make([]string, len(keys))
+	for i, key := range keys {
+		switch key {
+		case "std", "localmodule":
+			patterns[i] = key
+		default:
+			patterns[i] = regexp.QuoteMeta(key) + "(/.*)?"
+		}
+	}
+
+	return strings.Join(patterns, " || ")
+}
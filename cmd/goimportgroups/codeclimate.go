@@ -0,0 +1,92 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+// codeClimateIssue is an issue of a Code Climate report, the subset of it read by GitLab code quality.
+type codeClimateIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeClimateLocation `json:"location"`
+}
+
+type codeClimateLocation struct {
+	Path  string `json:"path"`
+	Lines struct {
+		Begin int `json:"begin"`
+	} `json:"lines"`
+}
+
+// codeClimateSeverities are the Code Climate severities of the severities of the issues.
+var codeClimateSeverities = map[string]string{
+	analyzer.SeverityError:   "major",
+	analyzer.SeverityWarning: "minor",
+}
+
+// codeClimateFormatter writes the issues as a single Code Climate report once all the files are checked, for GitLab
+// to show them in the code quality widget of merge requests.
+type codeClimateFormatter struct {
+	w      io.Writer
+	root   string
+	issues []codeClimateIssue
+}
+
+func (f *codeClimateFormatter) add(name string, issues []analyzer.Issue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	// GitLab maps the paths relative to the root of the repository to its files
+	path, err := rootRelative(f.root, name)
+	if err != nil {
+		return err
+	}
+
+	// the fingerprints leave the lines out, the messages too since some of them hold lines, for GitLab to tell an
+	// issue moved by the lines above it from a new one, counting the issues of the same rule, import and group to tell
+	// them apart
+	seen := make(map[string]int)
+	for _, iss := range issues {
+		key := fmt.Sprintf("%s\x00%s\x00%s\x00%s", path, iss.Code, iss.Path, iss.Expected)
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", key, seen[key])))
+		seen[key]++
+
+		ci := codeClimateIssue{
+			Description: iss.Message,
+			CheckName:   iss.Code,
+			Fingerprint: hex.EncodeToString(sum[:]),
+			Severity:    codeClimateSeverities[iss.Severity],
+		}
+		ci.Location.Path = path
+		ci.Location.Lines.Begin = iss.Pos.Line
+
+		f.issues = append(f.issues, ci)
+	}
+
+	return nil
+}
+
+func (f *codeClimateFormatter) flush() error {
+	issues := f.issues
+	if issues == nil {
+		issues = []codeClimateIssue{}
+	}
+
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.w.Write(append(data, '\n'))
+
+	return err
+}
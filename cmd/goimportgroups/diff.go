@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines kept around the changes in the hunks of a unified diff.
+const diffContext = 3
+
+// diffLine is a line of a unified diff, kind being ' ' for a line of both sources, '-' for a line removed from the
+// first and '+' for a line added by the second.
+type diffLine struct {
+	kind byte
+	text string
+}
+
+// unifiedDiff returns the unified diff turning the source a into the source b, with from and to as the names of the
+// two in its header, or an empty string if they are the same.
+func unifiedDiff(from, to string, a, b []byte) string {
+	lines := diffLines(splitLines(string(a)), splitLines(string(b)))
+
+	var changes []int
+	for i, l := range lines {
+		if l.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+
+	if len(changes) == 0 {
+		return ""
+	}
+
+	// aPos and bPos hold the line numbers, starting at 1, of the next line of each source at each line of the diff.
+	aPos, bPos := make([]int, len(lines)+1), make([]int, len(lines)+1)
+	aPos[0], bPos[0] = 1, 1
+	for i, l := range lines {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+		if l.kind != '+' {
+			aPos[i+1]++
+		}
+		if l.kind != '-' {
+			bPos[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", from, to)
+
+	for first := 0; first < len(changes); {
+		last := first
+		for last+1 < len(changes) && changes[last+1]-changes[last] <= 2*diffContext+1 {
+			last++
+		}
+
+		start := max(changes[first]-diffContext, 0)
+		end := min(changes[last]+diffContext+1, len(lines))
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			hunkRange(aPos[start], aPos[end]-aPos[start]), hunkRange(bPos[start], bPos[end]-bPos[start]))
+		for _, l := range lines[start:end] {
+			sb.WriteByte(l.kind)
+			sb.WriteString(l.text)
+			if !strings.HasSuffix(l.text, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		first = last + 1
+	}
+
+	return sb.String()
+}
+
+// hunkRange returns the range of count lines from line start in a hunk header. An empty range starts at the line
+// before the one it is at.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines returns the lines of s along with their line breaks.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines returns the lines of a shortest diff turning the lines a into the lines b. Only the lines between the
+// common prefix and suffix are compared, which stay few as the fixes only move the imports.
+func diffLines(a, b []string) []diffLine {
+	var prefix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	var suffix int
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var lines []diffLine
+	for _, l := range a[:prefix] {
+		lines = append(lines, diffLine{kind: ' ', text: l})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// common[i][j] is the length of the longest common subsequence of midA[i:] and midB[j:].
+	common := make([][]int, len(midA)+1)
+	for i := range common {
+		common[i] = make([]int, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			lines = append(lines, diffLine{kind: ' ', text: midA[i]})
+			i++
+			j++
+		case j == len(midB) || i < len(midA) && common[i+1][j] >= common[i][j+1]:
+			lines = append(lines, diffLine{kind: '-', text: midA[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{kind: '+', text: midB[j]})
+			j++
+		}
+	}
+
+	for _, l := range a[len(a)-suffix:] {
+		lines = append(lines, diffLine{kind: ' ', text: l})
+	}
+
+	return lines
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+// formatter writes the issues found by the command.
+type formatter interface {
+	// add records the issues of the file name, possibly writing them right away.
+	add(name string, issues []analyzer.Issue) error
+	// flush writes the issues recorded but not written yet.
+	flush() error
+}
+
+// newFormatter returns the formatter of the -format flag value format writing to w, the text one writing the issues
+// with the template messageFormat, colored if color is set.
+func newFormatter(
+	format string, w io.Writer, cfg analyzer.Config, messageFormat string, color bool,
+) (formatter, error) {
+	switch format {
+	case "text":
+		return newTextFormatter(w, messageFormat, color)
+	case "json":
+		return jsonFormatter{enc: json.NewEncoder(w)}, nil
+	case "sarif":
+		root, err := sourceRoot()
+		if err != nil {
+			return nil, err
+		}
+
+		return &sarifFormatter{w: w, root: root, docsURL: strings.TrimSuffix(cfg.DocsURL, "#")}, nil
+	case "teamcity":
+		return &teamcityFormatter{w: w, docsURL: strings.TrimSuffix(cfg.DocsURL, "#")}, nil
+	case "junit":
+		return &junitFormatter{w: w}, nil
+	case "codeclimate":
+		root, err := sourceRoot()
+		if err != nil {
+			return nil, err
+		}
+
+		return &codeClimateFormatter{w: w, root: root}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q, expected text, json, sarif, teamcity, junit or codeclimate", format)
+	}
+}
+
+// listFormatter writes the names of the files with errors, a line each, like gofmt -l.
+type listFormatter struct {
+	w io.Writer
+}
+
+func (f listFormatter) add(name string, issues []analyzer.Issue) error {
+	if !hasErrors(issues) {
+		return nil
+	}
+
+	_, err := fmt.Fprintln(f.w, name)
+
+	return err
+}
+
+func (f listFormatter) flush() error {
+	return nil
+}
+
+// nopFormatter writes nothing, for the diffs written by -d to stand alone.
+type nopFormatter struct{}
+
+func (nopFormatter) add(string, []analyzer.Issue) error {
+	return nil
+}
+
+func (nopFormatter) flush() error {
+	return nil
+}
+
+// jsonRecord is the JSON object written per issue by jsonFormatter.
+type jsonRecord struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Import   string `json:"import,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Message  string `json:"message"`
+}
+
+// jsonFormatter writes a JSON object per issue and line, for scripts to consume.
+type jsonFormatter struct {
+	enc *json.Encoder
+}
+
+func (f jsonFormatter) add(name string, issues []analyzer.Issue) error {
+	for _, iss := range issues {
+		err := f.enc.Encode(jsonRecord{
+			File:     name,
+			Line:     iss.Pos.Line,
+			Column:   iss.Pos.Column,
+			Code:     iss.Code,
+			Severity: iss.Severity,
+			Import:   iss.Path,
+			Expected: iss.Expected,
+			Message:  iss.Message,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f jsonFormatter) flush() error {
+	return nil
+}
+
+// teamcityEscaper escapes the values of the attributes of TeamCity service messages.
+var teamcityEscaper = strings.NewReplacer("|", "||", "'", "|'", "\n", "|n", "\r", "|r", "[", "|[", "]", "|]")
+
+// teamcityFormatter writes a TeamCity service message per issue, for TeamCity builds to show the issues as
+// inspections, preceded by the one of its rule the first time it occurs.
+type teamcityFormatter struct {
+	w       io.Writer
+	docsURL string
+	seen    map[string]bool
+}
+
+func (f *teamcityFormatter) add(name string, issues []analyzer.Issue) error {
+	for _, iss := range issues {
+		if !f.seen[iss.Code] {
+			if f.seen == nil {
+				f.seen = make(map[string]bool)
+			}
+
+			f.seen[iss.Code] = true
+			_, err := fmt.Fprintf(f.w,
+				"##teamcity[inspectionType id='%s' name='%[1]s' description='%s' category='goimportgroups']\n",
+				teamcityEscaper.Replace(iss.Code), teamcityEscaper.Replace(f.docsURL+"#"+iss.Code))
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := fmt.Fprintf(f.w,
+			"##teamcity[inspection typeId='%s' message='%s' file='%s' line='%d' SEVERITY='%s']\n",
+			teamcityEscaper.Replace(iss.Code), teamcityEscaper.Replace(iss.Message),
+			teamcityEscaper.Replace(filepath.ToSlash(name)), iss.Pos.Line, strings.ToUpper(iss.Severity))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *teamcityFormatter) flush() error {
+	return nil
+}
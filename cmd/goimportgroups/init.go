@@ -0,0 +1,180 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+// layout is a candidate configuration of groups proposed by init, a preset or groups of its own.
+type layout struct {
+	preset string
+	groups []configGroup
+}
+
+// layouts are the candidates of init, from the fewest groups to the most, the first of the ones followed by the most
+// files being proposed, along with the layout clustered from the blocks of imports of the files, coming last.
+var layouts = []layout{
+	{preset: "std-thirdparty"},
+	{preset: "std-thirdparty-local"},
+	{groups: []configGroup{
+		{Name: "std", Pattern: "std"},
+		{Name: "local", Pattern: "localmodule"},
+		{Name: "third-party", Pattern: ".*"},
+	}},
+	{preset: "std-thirdparty-org-local"},
+}
+
+// initConfig runs the init subcommand with args, writing to the configuration file of the current directory the
+// layout of groups followed by the most files of paths, and returns its exit code: 0 on success, 2 on errors.
+func initConfig(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("goimportgroups init", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	force := flags.Bool("force", false, "overwrite an existing configuration file")
+	configFile := bindConfigFlag(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if _, err := os.Stat(analyzer.ConfigFileName); !errors.Is(err, fs.ErrNotExist) && !*force {
+		fmt.Fprintf(stderr, "%s already exists, pass -force to overwrite it\n", analyzer.ConfigFileName)
+		return 2
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"./..."}
+	}
+
+	files, err := expandPaths(paths, loadOptions{tests: true})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	// the local module and the Go version of an existing configuration file apply to the candidates
+	module, err := resolveConfig(analyzer.DefaultConfig(), flags, *configFile)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	sources, err := readImporting(files)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	candidates := layouts
+	if l, ok := clusteredLayout(module, sources); ok {
+		candidates = append(candidates[:len(candidates):len(candidates)], l)
+	}
+
+	best, bestFollowing := candidates[0], 0
+	for _, l := range candidates {
+		following, err := countFollowing(l, module, sources)
+		if err != nil {
+			// a layout needing a local module the current directory lacks
+			continue
+		}
+
+		if following > bestFollowing {
+			best, bestFollowing = l, following
+		}
+	}
+
+	data, err := yaml.Marshal(struct {
+		Preset string        `yaml:"preset,omitempty"`
+		Groups []configGroup `yaml:"groups,omitempty"`
+	}{best.preset, best.groups})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if err := os.WriteFile(analyzer.ConfigFileName, data, 0o644); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	fmt.Fprintf(stdout, "wrote %s: %d of %d files with imports follow its groups\n", analyzer.ConfigFileName,
+		bestFollowing, len(sources))
+
+	return 0
+}
+
+// readImporting returns the sources of the files importing packages, the ones failing to parse left out.
+func readImporting(files []string) ([]analyzer.NamedSource, error) {
+	var sources []analyzer.NamedSource
+	for _, name := range files {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		f, err := parser.ParseFile(token.NewFileSet(), name, src, parser.ImportsOnly)
+		if err != nil || len(f.Imports) == 0 {
+			continue
+		}
+
+		sources = append(sources, analyzer.NamedSource{Name: name, Src: src})
+	}
+
+	return sources, nil
+}
+
+// countFollowing returns the number of sources whose blocks of imports follow the groups of l, with the local module
+// and the Go version of module.
+func countFollowing(l layout, module analyzer.Config, sources []analyzer.NamedSource) (int, error) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Preset = l.preset
+	cfg.LocalModule, cfg.GoVersion = module.LocalModule, module.GoVersion
+	// a layout of fewer groups would otherwise be followed by the files of a finer one
+	cfg.ReportSplitGroups = true
+	if l.groups != nil {
+		patterns := make([]string, len(l.groups))
+		for i, g := range l.groups {
+			patterns[i] = g.Pattern
+		}
+
+		cfg.Groups = strings.Join(patterns, ";")
+	}
+
+	c, err := analyzer.NewChecker(cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	following := 0
+	for _, r := range c.CheckFiles(sources) {
+		if r.Err == nil && !hasGroupIssues(r.Issues) {
+			following++
+		}
+	}
+
+	return following, nil
+}
+
+// hasGroupIssues reports whether any of issues is about the groups of the blocks of imports.
+func hasGroupIssues(issues []analyzer.Issue) bool {
+	for _, iss := range issues {
+		switch iss.Code {
+		case "group-order", "mixed-group", "split-group", "unmatched-import":
+			return true
+		}
+	}
+
+	return false
+}
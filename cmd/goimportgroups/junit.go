@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+// The subset of the JUnit XML report written by the command, as most CI systems read it.
+type (
+	junitTestSuites struct {
+		XMLName xml.Name         `xml:"testsuites"`
+		Suites  []junitTestSuite `xml:"testsuite"`
+	}
+
+	junitTestSuite struct {
+		Name     string          `xml:"name,attr"`
+		Tests    int             `xml:"tests,attr"`
+		Failures int             `xml:"failures,attr"`
+		Cases    []junitTestCase `xml:"testcase"`
+	}
+
+	junitTestCase struct {
+		Name      string        `xml:"name,attr"`
+		ClassName string        `xml:"classname,attr"`
+		Failure   *junitFailure `xml:"failure,omitempty"`
+		SystemOut string        `xml:"system-out,omitempty"`
+	}
+
+	junitFailure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Text    string `xml:",chardata"`
+	}
+)
+
+// junitFormatter writes a single JUnit XML report once all the files are checked, a test case per file failing with
+// its errors, for the CI systems showing JUnit reports only. The warnings are written to the output of the test case.
+type junitFormatter struct {
+	w     io.Writer
+	suite junitTestSuite
+}
+
+func (f *junitFormatter) add(name string, issues []analyzer.Issue) error {
+	tc := junitTestCase{Name: filepath.ToSlash(name), ClassName: "goimportgroups"}
+
+	var errs []analyzer.Issue
+	var errText, warnText strings.Builder
+	for _, iss := range issues {
+		text := &warnText
+		if iss.Severity == analyzer.SeverityError {
+			errs = append(errs, iss)
+			text = &errText
+		}
+
+		fmt.Fprintf(text, "%s: %s (%s)\n", iss.Pos, iss.Message, iss.Code)
+	}
+
+	if len(errs) > 0 {
+		tc.Failure = &junitFailure{
+			Message: fmt.Sprintf("%d import grouping issues", len(errs)),
+			Type:    errs[0].Code,
+			Text:    errText.String(),
+		}
+		f.suite.Failures++
+	}
+
+	tc.SystemOut = warnText.String()
+
+	f.suite.Tests++
+	f.suite.Cases = append(f.suite.Cases, tc)
+
+	return nil
+}
+
+func (f *junitFormatter) flush() error {
+	f.suite.Name = "goimportgroups"
+
+	if _, err := io.WriteString(f.w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(f.w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitTestSuites{Suites: []junitTestSuite{f.suite}}); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(f.w, "\n")
+
+	return err
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+// loadOptions are the options of the go command loading the packages of the patterns.
+type loadOptions struct {
+	tags  string
+	tests bool
+}
+
+// expandPaths returns the Go files the paths denote, in the order of the paths. The directories of a module and the
+// import path patterns are loaded with the go command, and the directories outside modules are walked.
+func expandPaths(paths []string, opts loadOptions) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		root, recursive := strings.CutSuffix(path, "/...")
+		if root == "" {
+			root = "/"
+		}
+
+		info, err := os.Stat(root)
+		if errors.Is(err, fs.ErrNotExist) && filepath.Ext(path) != ".go" {
+			// an import path pattern, or a missing directory the go command reports
+			loaded, err := loadPackages(".", path, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			files = append(files, loaded...)
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		module, err := analyzer.FindModulePath(root)
+		if err != nil {
+			return nil, err
+		}
+
+		var found []string
+		if module == "" {
+			found, err = walkDir(root, recursive)
+		} else if recursive {
+			found, err = loadPackages(root, "./...", opts)
+		} else {
+			found, err = loadPackages(root, ".", opts)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, found...)
+	}
+
+	return files, nil
+}
+
+// loadPackages returns the Go files of the packages matching pattern in dir, the ones of their tests included if
+// requested, as the go command selects them with the build tags of opts.
+func loadPackages(dir, pattern string, opts loadOptions) ([]string, error) {
+	cfg := &packages.Config{
+		Mode:  packages.NeedName | packages.NeedFiles,
+		Dir:   dir,
+		Tests: opts.tests,
+	}
+	if opts.tags != "" {
+		cfg.BuildFlags = []string{"-tags=" + opts.tags}
+	}
+
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load %s: %w", pattern, err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	var errs []error
+	seen := make(map[string]bool)
+	for _, pkg := range pkgs {
+		// the main package generated to run the tests
+		if strings.HasSuffix(pkg.ID, ".test") {
+			continue
+		}
+
+		// the errors of packages with files, like syntax errors, are the checker's to report
+		if len(pkg.GoFiles) == 0 {
+			for _, e := range pkg.Errors {
+				errs = append(errs, e)
+			}
+		}
+
+		for _, name := range pkg.GoFiles {
+			if name = relativePath(wd, name); !seen[name] {
+				seen[name] = true
+				files = append(files, name)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("%s matches no packages", pattern)
+	}
+
+	return files, nil
+}
+
+// relativePath returns the absolute path name relative to the working directory wd, as the paths walked are, unless
+// it is outside of wd.
+func relativePath(wd, name string) string {
+	for _, dir := range []string{wd, resolveSymlinks(wd)} {
+		rel, err := filepath.Rel(dir, name)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return rel
+		}
+	}
+
+	return name
+}
+
+// resolveSymlinks returns name with its symbolic links resolved, or as it is if they cannot be.
+func resolveSymlinks(name string) string {
+	if resolved, err := filepath.EvalSymlinks(name); err == nil {
+		return resolved
+	}
+
+	return name
+}
+
+// walkDir returns the Go files of the directory root, or of its tree if recursive is set.
+func walkDir(root string, recursive bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if name != root && (!recursive || ignoredDir(d.Name())) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if filepath.Ext(name) == ".go" {
+			files = append(files, name)
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+// ignoredDir reports whether the go command ignores the directories named name in patterns like ./... .
+func ignoredDir(name string) bool {
+	return name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// changedSince returns the files changed since the git revision ref, in the working tree included, along with the
+// untracked ones not ignored.
+func changedSince(files []string, ref string) ([]string, error) {
+	top, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+
+	modified, err := git("diff", "--name-only", "--diff-filter=d", "--no-renames", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := git("ls-files", "--others", "--exclude-standard", "--full-name", "--", ":/")
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[string]bool)
+	for _, name := range strings.Fields(modified + "\n" + untracked) {
+		changed[filepath.Join(top, filepath.FromSlash(name))] = true
+	}
+
+	var kept []string
+	for _, name := range files {
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			return nil, err
+		}
+
+		// git resolves the symbolic links of the path of the working tree
+		if changed[resolveSymlinks(abs)] {
+			kept = append(kept, name)
+		}
+	}
+
+	return kept, nil
+}
+
+// sourceRoot returns the directory the reports name the files relative to, the root of the git working tree of the
+// current directory, or the current directory outside of git.
+func sourceRoot() (string, error) {
+	if top, err := git("rev-parse", "--show-toplevel"); err == nil && top != "" {
+		return resolveSymlinks(top), nil
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	return resolveSymlinks(wd), nil
+}
+
+// rootRelative returns the path of the file name relative to root, with forward slashes, or its absolute one if it
+// is outside of root.
+func rootRelative(root, name string) (string, error) {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return "", err
+	}
+
+	abs = resolveSymlinks(abs)
+
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(abs), nil
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+// git runs git with args in the current directory and returns its output, trimmed.
+func git(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return "", fmt.Errorf("git %s: %s", args[0], bytes.TrimSpace(exitErr.Stderr))
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,334 @@
+// Command goimportgroups checks that the imports of Go files are separated into the configured groups, and rewrites
+// the files to fix them with -w.
+//
+// Usage:
+//
+//	goimportgroups [flags] [path ...]
+//	goimportgroups [flags] -
+//	goimportgroups init [-force] [-config file] [path ...]
+//	goimportgroups match [flags] importpath ...
+//	goimportgroups migrate [-from gci] [file]
+//	goimportgroups migrate -from goimports-reviser [-project-name path] [-company-prefixes list] [-imports-order list]
+//
+// A path is a Go file or a package pattern of the go command, like ./... or github.com/org/app/..., whose Go files
+// are checked, test files included unless -test=false, as selected by the build constraints and -tags. Directories
+// outside modules are walked instead, a directory followed by /... standing for its tree of Go files, skipping
+// testdata, vendor and the directories whose name starts with a dot or an underscore. Without paths, ./... is checked.
+//
+// The configuration file given with -config, or the .goimportgroups.yaml of the current directory or the closest of
+// its parents, configures the command and its subcommands, the flags set explicitly taking precedence over it.
+//
+// With -since, only the files changed since the given git revision are checked, the ones of the working tree and the
+// untracked ones included, for quick checks of the changes before a push or in a pull request.
+//
+// With - as the only path, the source read from the standard input is written to the standard output with the fixes
+// applied, for editors to run the command as a filter on save.
+//
+// With -d, the diffs of the fixes are written instead, in the unified format, like gofmt -d does, leaving the files
+// as they are.
+//
+// With -baseline write, the files with issues are recorded in the -baseline-file along with a hash of their imports,
+// for -baseline check to report the issues of the other files only, and of the recorded files whose imports changed.
+//
+// The init subcommand writes a .goimportgroups.yaml in the current directory with the layout of groups followed by
+// the most files of the paths, among the presets, the standard library, local module and third party one, and the one
+// clustered from the blocks of imports of the files.
+//
+// The match subcommand writes the group each import path belongs to with the flags of the configuration, along with
+// the evaluation of the patterns of the groups, a line per subexpression, to debug complex patterns.
+//
+// The migrate subcommand writes the configuration file equivalent to the gci sections of the golangci-lint
+// configuration file, .golangci.yml by default, or of a gci one, or to the flags of goimports-reviser, for teams
+// switching from either.
+//
+// With -l, only the names of the files with issues are written, a line each, like gofmt -l does. Otherwise, the issues
+// are written a line each, as a JSON object a line each with -format json, as a single SARIF log, for GitHub code
+// scanning and the other SARIF consumers, with -format sarif, as TeamCity inspections with -format teamcity, or as a
+// JUnit XML report of a test case per file with -format junit, or as a Code Climate report for GitLab code quality
+// with -format codeclimate.
+//
+// The lines of the text format are written with the Go template of -message-format, given the Pos, File, Line,
+// Column, Code, Severity, Message, Path and Expected of each issue, and colored in terminals, the offending import
+// highlighted, unless -color never or NO_COLOR is set.
+//
+// The issues of the rules given the warning severity with -severity or -severities are reported like the others, but
+// the command exits with 1 for the issues of severity error only, and lists with -l the files with such issues only.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command with args and returns its exit code: 0 if no issues of severity error remain, 1 if some do, 2
+// on errors.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "migrate" {
+		return migrate(args[1:], stdout, stderr)
+	}
+
+	if len(args) > 0 && args[0] == "init" {
+		return initConfig(args[1:], stdout, stderr)
+	}
+
+	if len(args) > 0 && args[0] == "match" {
+		return match(args[1:], stdout, stderr)
+	}
+
+	flags := flag.NewFlagSet("goimportgroups", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	cfg := analyzer.DefaultConfig()
+	analyzer.BindFlags(flags, &cfg)
+	configFile := bindConfigFlag(flags)
+	write := flags.Bool("w", false, "write the fixes to the files instead of only reporting the issues")
+	format := flags.String("format", "text",
+		"output format of the issues, text, json, sarif, teamcity, junit or codeclimate")
+	messageFormat := flags.String("message-format", "",
+		"Go template of the lines of the text format per issue, "+defaultMessageFormat+" by default")
+	colorMode := flags.String("color", "auto", "color the text format, auto for terminals only, always or never")
+	list := flags.Bool("l", false, "list the names of the files with issues instead of the issues")
+	diff := flags.Bool("d", false, "write the diffs of the fixes instead of the issues, without writing the files")
+	baselineMode := flags.String("baseline", "",
+		"write to record the files with issues in the -baseline-file, check to report the issues of the other files only")
+	baselinePath := flags.String("baseline-file", ".goimportgroups-baseline.json", "path of the baseline file")
+	since := flags.String("since", "", "check only the files changed since the git revision, untracked ones included")
+	var opts loadOptions
+	flags.StringVar(&opts.tags, "tags", "", "comma separated build tags selecting the files of the packages")
+	flags.BoolVar(&opts.tests, "test", true, "check the test files of the packages too")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := resolveConfig(cfg, flags, *configFile)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	c, err := analyzer.NewChecker(cfg)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	color, err := useColor(*colorMode, stdout)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	out, err := newFormatter(*format, stdout, cfg, *messageFormat, color)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if *list {
+		out = listFormatter{w: stdout}
+	}
+
+	if *diff {
+		out = nopFormatter{}
+	}
+
+	base, err := newBaseline(*baselineMode, *baselinePath)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"./..."}
+	}
+
+	if len(paths) == 1 && paths[0] == "-" {
+		return filter(c, stdin, stdout, stderr)
+	}
+
+	files, err := expandPaths(paths, opts)
+	if err == nil && *since != "" {
+		files, err = changedSince(files, *since)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	code := 0
+	for _, name := range files {
+		var issues []analyzer.Issue
+		if *diff {
+			issues, err = diffFile(c, name, stdout)
+		} else {
+			issues, err = checkFile(c, name, *write)
+		}
+		if err == nil {
+			issues, err = base.filter(name, issues)
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			code = 2
+			continue
+		}
+
+		if err := out.add(name, issues); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+
+		if hasErrors(issues) && code == 0 {
+			code = 1
+		}
+	}
+
+	if err := out.flush(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if err := base.save(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	return code
+}
+
+// checkFile returns the issues of the file name, after applying their fixes to it if write is set.
+func checkFile(c *analyzer.Checker, name string, write bool) ([]analyzer.Issue, error) {
+	src, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !write {
+		return c.CheckBytes(name, src)
+	}
+
+	fixed, issues, err := c.FixBytes(name, src)
+	if err != nil || string(fixed) == string(src) {
+		return issues, err
+	}
+
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return issues, os.WriteFile(name, fixed, info.Mode().Perm())
+}
+
+// diffFile writes to w the unified diff of the fixes of the issues of the file name, and returns the issues found
+// before the fixes.
+func diffFile(c *analyzer.Checker, name string, w io.Writer) ([]analyzer.Issue, error) {
+	src, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := c.CheckBytes(name, src)
+	if err != nil || len(issues) == 0 {
+		return issues, err
+	}
+
+	fixed, _, err := c.FixBytes(name, src)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = io.WriteString(w, unifiedDiff(name+".orig", name, src, fixed))
+
+	return issues, err
+}
+
+// filter reads a source from stdin and writes it to stdout with the fixes of its issues applied, like gofmt does
+// without paths, for editors to run on save. The issues left are not reported.
+func filter(c *analyzer.Checker, stdin io.Reader, stdout, stderr io.Writer) int {
+	src, err := io.ReadAll(stdin)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	fixed, _, err := c.FixBytes("<standard input>", src)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if _, err := stdout.Write(fixed); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	return 0
+}
+
+// hasErrors reports whether any of issues has severity error.
+func hasErrors(issues []analyzer.Issue) bool {
+	for _, iss := range issues {
+		if iss.Severity == analyzer.SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bindConfigFlag defines on flags the -config flag of the configuration file of the command.
+func bindConfigFlag(flags *flag.FlagSet) *string {
+	return flags.String("config", "", "YAML configuration file, by default "+analyzer.ConfigFileName+
+		" in the current directory or the closest of its parents, if any (flags set explicitly take precedence)")
+}
+
+// resolveConfig returns cfg, bound to flags, over the configuration file name, or the one found in the current
+// directory or the closest of its parents if empty, if any, with the local module and the Go version resolved.
+func resolveConfig(cfg analyzer.Config, flags *flag.FlagSet, name string) (analyzer.Config, error) {
+	if name == "" {
+		var err error
+		name, err = analyzer.FindConfigFile(".")
+		if err != nil {
+			return analyzer.Config{}, err
+		}
+	}
+
+	if name != "" {
+		fileCfg, err := analyzer.LoadConfigFile(name, analyzer.DefaultConfig())
+		if err != nil {
+			return analyzer.Config{}, err
+		}
+
+		cfg = analyzer.OverrideConfig(fileCfg, flags)
+	}
+
+	return cfg, resolveModule(&cfg)
+}
+
+// resolveModule sets the local module and the Go version of cfg left empty to the ones of the go.mod of the current
+// directory.
+func resolveModule(cfg *analyzer.Config) error {
+	var err error
+	if cfg.LocalModule == "" {
+		cfg.LocalModule, err = analyzer.FindModulePath(".")
+		if err != nil {
+			return err
+		}
+	}
+
+	if cfg.GoVersion == "" {
+		cfg.GoVersion, err = analyzer.FindGoVersion(".")
+	}
+
+	return err
+}
@@ -0,0 +1,1035 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+const swappedSrc = `package main
+
+import (
+	"time"
+
+	"fmt"
+)
+
+var _, _ = time.Now, fmt.Println
+`
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"main.go", filepath.Join("sub", "sub.go"), filepath.Join("testdata", "bad.go")} {
+		err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = os.WriteFile(filepath.Join(dir, name), []byte(swappedSrc), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-groups", "fmt;time", dir}, nil, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit code 1, got %d: %s", code, stderr.String())
+	}
+
+	if lines := strings.Split(strings.TrimSpace(stdout.String()), "\n"); len(lines) != 1 ||
+		!strings.Contains(lines[0], "main.go:6:2: import \"fmt\"") {
+		t.Errorf("expected the issue of main.go only, got %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"-groups", "fmt;time", "-w", dir + "/..."}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0 after fixing, got %d: %s%s", code, stdout.String(), stderr.String())
+	}
+
+	want := "package main\n\nimport (\n\t\"fmt\"\n\n\t\"time\"\n)\n\nvar _, _ = time.Now, fmt.Println\n"
+	for name, expected := range map[string]string{
+		"main.go":                           want,
+		filepath.Join("sub", "sub.go"):      want,
+		filepath.Join("testdata", "bad.go"): swappedSrc,
+	} {
+		src, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(src) != expected {
+			t.Errorf("unexpected content of %s:\n%s", name, src)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(swappedSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"-groups", "fmt;time", "-format", "sarif", dir}, nil, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit code 1, got %d: %s", code, stderr.String())
+	}
+
+	var log struct {
+		Version string
+		Runs    []struct {
+			Results []struct {
+				RuleID    string
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI string }
+						Region           struct{ StartLine int }
+					}
+				}
+				Fixes []struct{}
+			}
+		}
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 {
+		t.Fatalf("expected a single result, got %s", stdout.String())
+	}
+
+	result := log.Runs[0].Results[0]
+	location := result.Locations[0].PhysicalLocation
+	if result.RuleID != "group-order" || !strings.HasSuffix(location.ArtifactLocation.URI, "/main.go") ||
+		location.Region.StartLine != 6 || len(result.Fixes) != 1 {
+		t.Errorf("unexpected result %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"-groups", "fmt;time", "-format", "json", dir}, nil, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit code 1, got %d: %s", code, stderr.String())
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+
+	if record["line"] != 6.0 || record["column"] != 2.0 || record["import"] != "fmt" || record["expected"] != "fmt" ||
+		record["code"] != "group-order" {
+		t.Errorf("unexpected record %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"-groups", "fmt;time", "-l", dir + "/..."}, nil, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit code 1, got %d: %s", code, stderr.String())
+	}
+
+	if got, want := stdout.String(), filepath.Join(dir, "main.go")+"\n"; got != want {
+		t.Errorf("expected %q to be listed, got %q", want, got)
+	}
+
+	if code := run([]string{"-format", "xml", dir}, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for an unknown format, got %d", code)
+	}
+
+	if code := run([]string{filepath.Join(dir, "missing.go")}, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for a missing file, got %d", code)
+	}
+}
+
+func TestRunPackages(t *testing.T) {
+	dir := t.TempDir()
+
+	for name, src := range map[string]string{
+		"go.mod":                            "module example.com/app\n\ngo 1.21\n",
+		"main.go":                           swappedSrc,
+		"main_test.go":                      swappedSrc,
+		"tagged.go":                         "//go:build special\n\n" + swappedSrc,
+		filepath.Join("sub", "sub.go"):      strings.Replace(swappedSrc, "package main", "package sub", 1),
+		filepath.Join("testdata", "bad.go"): swappedSrc,
+	} {
+		err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		args  []string
+		files []string
+	}{
+		{args: []string{dir + "/..."}, files: []string{"main.go", "main_test.go", "sub.go"}},
+		{args: []string{"-test=false", dir + "/..."}, files: []string{"main.go", "sub.go"}},
+		{args: []string{"-tags", "special", dir}, files: []string{"main.go", "main_test.go", "tagged.go"}},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(append([]string{"-groups", "fmt;time"}, tc.args...), nil, &stdout, &stderr); code != 1 {
+			t.Fatalf("expected exit code 1 for %v, got %d: %s", tc.args, code, stderr.String())
+		}
+
+		var files []string
+		for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+			name, _, _ := strings.Cut(line, ":")
+			files = append(files, filepath.Base(name))
+		}
+
+		sort.Strings(files)
+		if strings.Join(files, ",") != strings.Join(tc.files, ",") {
+			t.Errorf("expected issues in %v for %v, got %s", tc.files, tc.args, stdout.String())
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	// the files of the packages are named relative to the current directory, as the ones walked are
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-groups", "fmt;time", "-l", "./..."}, nil, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit code 1 for ./..., got %d: %s", code, stderr.String())
+	}
+
+	got := strings.Fields(stdout.String())
+	sort.Strings(got)
+
+	want := []string{"main.go", "main_test.go", filepath.Join("sub", "sub.go")}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected the files %q for ./..., got %q", want, got)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"example.com/missing/..."}, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for a pattern matching no packages, got %d: %s", code, stdout.String())
+	}
+}
+
+func TestRunFilter(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-groups", "fmt;time", "-"}, strings.NewReader(swappedSrc), &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	want := "package main\n\nimport (\n\t\"fmt\"\n\n\t\"time\"\n)\n\nvar _, _ = time.Now, fmt.Println\n"
+	if stdout.String() != want {
+		t.Errorf("expected the regrouped source\n%s\ngot\n%s", want, stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"-"}, strings.NewReader("package"), &stdout, &stderr); code != 2 || stdout.Len() > 0 {
+		t.Errorf("expected exit code 2 and no output for an invalid source, got %d: %s", code, stdout.String())
+	}
+}
+
+func TestRunConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	data := "groups:\n  - pattern: fmt\n  - pattern: time\n"
+	if err := os.WriteFile(filepath.Join(dir, ".goimportgroups.yaml"), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	other := filepath.Join(dir, "other.yaml")
+	if err := os.WriteFile(other, []byte("groups:\n  - pattern: time\n  - pattern: fmt\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "main.go"), []byte(swappedSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(sub); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	// the configuration file of the parent directory applies
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"main.go"}, nil, &stdout, &stderr)
+	if code != 1 || !strings.Contains(stdout.String(), "main.go:6:2") {
+		t.Errorf("expected the issue of main.go with the groups of the file, got %d: %s%s", code, stdout.String(),
+			stderr.String())
+	}
+
+	for name, args := range map[string][]string{
+		"explicit flags": {"-groups", ".*", "main.go"},
+		"-config":        {"-config", other, "main.go"},
+	} {
+		stdout.Reset()
+		if code := run(args, nil, &stdout, &stderr); code != 0 {
+			t.Errorf("%s: expected exit code 0, got %d: %s%s", name, code, stdout.String(), stderr.String())
+		}
+	}
+
+	stdout.Reset()
+	if code := run([]string{"-"}, strings.NewReader(swappedSrc), &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0 filtering, got %d: %s", code, stderr.String())
+	}
+
+	if want := "package main\n\nimport (\n\t\"fmt\"\n\n\t\"time\"\n)\n"; !strings.HasPrefix(stdout.String(), want) {
+		t.Errorf("expected the source regrouped with the groups of the file, got\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"match", "time"}, nil, &stdout, &stderr); code != 0 ||
+		!strings.Contains(stdout.String(), `"time" belongs to group 2`) {
+		t.Errorf("expected time to belong to group 2 of the file, got %d: %s%s", code, stdout.String(), stderr.String())
+	}
+}
+
+func TestRunDiff(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(name, []byte(swappedSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-groups", "fmt;time", "-d", name}, nil, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit code 1, got %d: %s", code, stderr.String())
+	}
+
+	want := "--- " + name + ".orig\n+++ " + name + "\n" +
+		"@@ -1,9 +1,9 @@\n package main\n \n import (\n-\t\"time\"\n-\n \t\"fmt\"\n+\n+\t\"time\"\n )\n \n var _, _ = time.Now, fmt.Println\n"
+	if stdout.String() != want {
+		t.Errorf("expected the diff\n%s\ngot\n%s", want, stdout.String())
+	}
+
+	if src, err := os.ReadFile(name); err != nil || string(src) != swappedSrc {
+		t.Errorf("expected the file to be left as is, got %q, %v", src, err)
+	}
+}
+
+func TestRunBaseline(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.go", "b.go"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(swappedSrc), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := filepath.Join(dir, "baseline.json")
+	args := []string{"-groups", "fmt;time", "-baseline-file", path, "-baseline"}
+
+	var stdout, stderr bytes.Buffer
+	if code := run(append(args, "write", dir), nil, &stdout, &stderr); code != 0 || stdout.Len() > 0 {
+		t.Fatalf("expected exit code 0 and no issues recording the baseline, got %d: %s%s",
+			code, stdout.String(), stderr.String())
+	}
+
+	if code := run(append(args, "check", dir), nil, &stdout, &stderr); code != 0 || stdout.Len() > 0 {
+		t.Fatalf("expected exit code 0 and no issues against the baseline, got %d: %s%s",
+			code, stdout.String(), stderr.String())
+	}
+
+	changed := strings.Replace(swappedSrc, `"fmt"`, `"fmt"
+	"os"`, 1)
+	if err := os.WriteFile(filepath.Join(dir, "b.go"), []byte(changed), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := run(append(args, "check", dir), nil, &stdout, &stderr); code != 1 ||
+		!strings.Contains(stdout.String(), "b.go") || strings.Contains(stdout.String(), "a.go") {
+		t.Errorf("expected exit code 1 and the issues of b.go only, got %d: %s%s", code, stdout.String(), stderr.String())
+	}
+}
+
+func TestRunBaselineRelative(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte(swappedSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-groups", "fmt;time", "-baseline", "write", "."}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0 recording the baseline, got %d: %s", code, stderr.String())
+	}
+
+	data, err := os.ReadFile(".goimportgroups-baseline.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), `"file": "a.go"`) {
+		t.Fatalf("expected a.go recorded relative to the baseline file, got\n%s", data)
+	}
+
+	// checking from another directory, with the paths of the files and the baseline given from there
+	if err := os.Chdir(filepath.Dir(dir)); err != nil {
+		t.Fatal(err)
+	}
+
+	args := []string{
+		"-groups", "fmt;time", "-baseline", "check",
+		"-baseline-file", filepath.Join(filepath.Base(dir), ".goimportgroups-baseline.json"),
+		filepath.Join(filepath.Base(dir), "a.go"),
+	}
+	if code := run(args, nil, &stdout, &stderr); code != 0 || stdout.Len() > 0 {
+		t.Errorf("expected exit code 0 and no issues against the baseline, got %d: %s%s",
+			code, stdout.String(), stderr.String())
+	}
+}
+
+func TestRunSince(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"a.go", "b.go"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(swappedSrc), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	for _, name := range []string{"b.go", "c.go"} {
+		src := strings.Replace(swappedSrc, "package main", "package main // changed", 1)
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-groups", "fmt;time", "-l", "-since", "HEAD"}, nil, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit code 1, got %d: %s", code, stderr.String())
+	}
+
+	if got := strings.Fields(stdout.String()); strings.Join(got, ",") != "b.go,c.go" {
+		t.Errorf("expected the changed files b.go and c.go, got %q", got)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"-groups", "fmt;time", "-since", "missing"}, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for an unknown revision, got %d: %s", code, stdout.String())
+	}
+}
+
+func TestRunMigrate(t *testing.T) {
+	name := filepath.Join(t.TempDir(), ".golangci.yml")
+	settings := "linters-settings:\n  gci:\n    sections:\n" +
+		"      - default\n      - prefix(github.com/org, example.com)\n      - standard\n      - blank\n"
+	if err := os.WriteFile(name, []byte(settings), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"migrate", "-from", "gci", name}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	if !strings.Contains(stderr.String(), `"blank" has no equivalent`) {
+		t.Errorf("expected a warning about the blank section, got %q", stderr.String())
+	}
+
+	// without custom-order, gci orders the sections by kind
+	want := "groups:\n" +
+		"    - name: standard\n      pattern: std\n" +
+		"    - name: default\n      pattern: .* && !(github\\.com/org.* || example\\.com.*)\n" +
+		"    - name: prefix(github.com/org, example.com)\n      pattern: github\\.com/org.* || example\\.com.*\n"
+	if !strings.HasSuffix(stdout.String(), want) {
+		t.Fatalf("expected the groups\n%s\ngot\n%s", want, stdout.String())
+	}
+
+	// the command reads the configuration file written
+	dir := t.TempDir()
+	gci := "package main\n\nimport (\n\t\"fmt\"\n\n\t\"golang.org/x/mod\"\n\n\t\"github.com/org/app\"\n)\n"
+	for name, src := range map[string]string{
+		".goimportgroups.yaml": stdout.String(),
+		"gci.go":               gci,
+		"reversed.go":          "package main\n\nimport (\n\t\"github.com/org/app\"\n\n\t\"fmt\"\n)\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	stdout.Reset()
+	if code := run([]string{"-l", "."}, nil, &stdout, &stderr); code != 1 || stdout.String() != "reversed.go\n" {
+		t.Errorf("expected the imports grouped like gci to pass and the others to fail, got %d: %s%s", code,
+			stdout.String(), stderr.String())
+	}
+
+	if code := run([]string{"migrate", "-from", "goimports", name}, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for an unknown linter, got %d", code)
+	}
+}
+
+func TestRunMigrateLocalModule(t *testing.T) {
+	name := filepath.Join(t.TempDir(), ".golangci.yml")
+	settings := "linters-settings:\n  gci:\n    sections:\n      - standard\n      - default\n" +
+		"      - prefix(github.com/acme,github.com/foo)\n      - blank\n      - dot\n      - localmodule\n"
+	if err := os.WriteFile(name, []byte(settings), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"migrate", "-from", "gci", name}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	// gci puts the imports of the local module in its section, even if a prefix matches them too
+	want := "    - name: prefix(github.com/acme,github.com/foo)\n" +
+		"      pattern: (github\\.com/acme.* || github\\.com/foo.*) && !(localmodule)\n" +
+		"    - name: localmodule\n      pattern: localmodule\n"
+	if !strings.HasSuffix(stdout.String(), want) {
+		t.Fatalf("expected the groups to end with\n%s\ngot\n%s", want, stdout.String())
+	}
+
+	src := "package main\n\nimport (\n\t\"fmt\"\n\n\t\"golang.org/x/mod\"\n\n\t\"github.com/acme/lib\"\n" +
+		"\t\"github.com/foo/bar\"\n\n\t\"github.com/acme/app/sub\"\n)\n"
+	checkMigrated(t, stdout.Bytes(), "github.com/acme/app", src)
+}
+
+// checkMigrated checks that src, in the module localModule, follows the groups of the configuration file config.
+func checkMigrated(t *testing.T, config []byte, localModule, src string) {
+	t.Helper()
+
+	name := filepath.Join(t.TempDir(), ".goimportgroups.yaml")
+	if err := os.WriteFile(name, config, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := analyzer.LoadConfigFile(name, analyzer.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.LocalModule = localModule
+	if issues, err := analyzer.Check([]byte(src), cfg); err != nil || len(issues) > 0 {
+		t.Errorf("expected the imports grouped like the migrated linter to pass, got %v, %v", issues, err)
+	}
+}
+
+func TestRunMigrateReviser(t *testing.T) {
+	args := []string{
+		"migrate", "-from", "goimports-reviser", "-project-name", "github.com/org/app",
+		"-company-prefixes", "github.com/org", "-imports-order", "std,general,project,company,blanked",
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run(args, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	if !strings.Contains(stderr.String(), `"blanked" has no equivalent`) {
+		t.Errorf("expected a warning about the blanked group, got %q", stderr.String())
+	}
+
+	want := "groups:\n" +
+		"    - name: std\n      pattern: std\n" +
+		"    - name: general\n      pattern: .* && !(github\\.com/org/app.* || github\\.com/org.*)\n" +
+		"    - name: project\n      pattern: github\\.com/org/app.*\n" +
+		"    - name: company\n      pattern: github\\.com/org.*\n"
+	if !strings.HasSuffix(stdout.String(), want) {
+		t.Errorf("expected the groups\n%s\ngot\n%s", want, stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"migrate", "-from", "goimports-reviser"}, nil, &stdout, &stderr); code != 0 ||
+		!strings.Contains(stdout.String(), "pattern: localmodule") {
+		t.Errorf("expected the project group to match the local module without -project-name, got %d: %s",
+			code, stdout.String())
+	}
+
+	// the company prefix holds the project, whose imports goimports-reviser puts in the project group
+	stdout.Reset()
+	args = []string{"migrate", "-from", "goimports-reviser", "-company-prefixes", "github.com/acme"}
+	if code := run(args, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	want = "    - name: company\n      pattern: github\\.com/acme.* && !(localmodule)\n" +
+		"    - name: project\n      pattern: localmodule\n"
+	if !strings.HasSuffix(stdout.String(), want) {
+		t.Fatalf("expected the groups to end with\n%s\ngot\n%s", want, stdout.String())
+	}
+
+	src := "package main\n\nimport (\n\t\"fmt\"\n\n\t\"golang.org/x/mod\"\n\n\t\"github.com/acme/lib\"\n\n" +
+		"\t\"github.com/acme/app/sub\"\n)\n"
+	checkMigrated(t, stdout.Bytes(), "github.com/acme/app", src)
+}
+
+func TestRunInit(t *testing.T) {
+	dir := t.TempDir()
+
+	local := "package main\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/lib\"\n\n\t\"github.com/org/app/sub\"\n)\n"
+	for name, src := range map[string]string{
+		"go.mod": "module github.com/org/app\n\ngo 1.21\n\n" +
+			"require example.com/lib v0.0.0\n\nreplace example.com/lib => ./lib\n",
+		filepath.Join("lib", "go.mod"): "module example.com/lib\n\ngo 1.21\n",
+		filepath.Join("lib", "lib.go"): "package lib\n",
+		"a.go":                         local,
+		"b.go":                         local,
+		"c.go":                         swappedSrc,
+		filepath.Join("sub", "sub.go"): "package sub\n\nimport \"fmt\"\n\nvar _ = fmt.Println\n",
+	} {
+		err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"init"}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	if !strings.Contains(stdout.String(), "3 of 4 files") {
+		t.Errorf("expected 3 of the 4 files to follow the groups, got %q", stdout.String())
+	}
+
+	config, err := os.ReadFile(analyzer.ConfigFileName)
+	if err != nil || string(config) != "preset: std-thirdparty-local\n" {
+		t.Errorf("expected the std-thirdparty-local preset, got %q, %v", config, err)
+	}
+
+	if code := run([]string{"init"}, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for an existing configuration file, got %d", code)
+	}
+
+	if code := run([]string{"init", "-force"}, nil, &stdout, &stderr); code != 0 {
+		t.Errorf("expected exit code 0 overwriting the configuration file, got %d: %s", code, stderr.String())
+	}
+}
+
+func TestRunInitClustered(t *testing.T) {
+	dir := t.TempDir()
+
+	// github.com/other has a block of its own, after the one of the rest of the third party packages
+	three := "package main\n\nimport (\n\t\"fmt\"\n\n\t\"github.com/x/y\"\n\t\"gopkg.in/yaml.v3\"\n\n" +
+		"\t\"github.com/other/z\"\n)\n"
+	for name, src := range map[string]string{
+		"a.go": three,
+		"b.go": three,
+		"c.go": "package main\n\nimport (\n\t\"os\"\n\n\t\"github.com/x/w\"\n\n\t\"github.com/other/z/sub\"\n)\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"init"}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	if !strings.Contains(stdout.String(), "3 of 3 files") {
+		t.Errorf("expected the 3 files to follow the groups, got %q", stdout.String())
+	}
+
+	cfg, err := analyzer.LoadConfigFile(analyzer.ConfigFileName, analyzer.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groups := `std;.* && !(std || github\.com/other(/.*)?);github\.com/other(/.*)?`
+	if cfg.Groups != groups || cfg.GroupNames != "std;third-party;github.com/other" {
+		t.Errorf("expected the groups %s named std, third-party and github.com/other, got %s named %s", groups,
+			cfg.Groups, cfg.GroupNames)
+	}
+
+	// the command checks the files with the groups written
+	stdout.Reset()
+	if code := run(nil, nil, &stdout, &stderr); code != 0 {
+		t.Errorf("expected exit code 0 with the configuration file written, got %d: %s%s", code, stdout.String(),
+			stderr.String())
+	}
+}
+
+func TestRunMatch(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"match", "-groups", "fmt;.*,!time", "fmt", "time"}, nil, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit code 1 for a path of no group, got %d: %s", code, stderr.String())
+	}
+
+	for _, want := range []string{`"fmt" belongs to group 1`, "  .* && !time: does not match\n", `"time" belongs to no group`} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("expected %q in the output, got\n%s", want, stdout.String())
+		}
+	}
+
+	if code := run([]string{"match", "-groups", "fmt"}, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 without import paths, got %d", code)
+	}
+}
+
+func TestRunSARIFSourceRoot(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "main.go"), []byte(swappedSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(sub); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-groups", "fmt;time", "-format", "sarif", "main.go"}, nil, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit code 1, got %d: %s", code, stderr.String())
+	}
+
+	type artifactLocation struct {
+		URI       string
+		URIBaseID string `json:"uriBaseId"`
+	}
+
+	var log struct {
+		Runs []struct {
+			OriginalURIBaseIDs map[string]artifactLocation `json:"originalUriBaseIds"`
+			Results            []struct {
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation artifactLocation
+					}
+				}
+			}
+		}
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 {
+		t.Fatalf("expected a single result, got %s", stdout.String())
+	}
+
+	want := artifactLocation{URI: "sub/main.go", URIBaseID: "%SRCROOT%"}
+	if got := log.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation; got != want {
+		t.Errorf("expected the location %+v relative to the source root, got %+v", want, got)
+	}
+
+	root := log.Runs[0].OriginalURIBaseIDs["%SRCROOT%"].URI
+	if !strings.HasPrefix(root, "file:///") || !strings.HasSuffix(root, "/") {
+		t.Errorf("expected the file URI of the directory of the repository as the source root, got %q", root)
+	}
+}
+
+func TestRunTeamCity(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(name, []byte(swappedSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-groups", "fmt;time", "-format", "teamcity", name}, nil, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit code 1, got %d: %s", code, stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "##teamcity[inspectionType id='group-order' ") {
+		t.Fatalf("expected the inspection type of group-order then its inspection, got\n%s", stdout.String())
+	}
+
+	want := "##teamcity[inspection typeId='group-order' message='import \"fmt\" belongs to group \"fmt\" (group 1) " +
+		"but appears after group 2 (\"time\")' file='" + filepath.ToSlash(name) + "' line='6' SEVERITY='ERROR']"
+	if lines[1] != want {
+		t.Errorf("expected the inspection\n%s\ngot\n%s", want, lines[1])
+	}
+}
+
+func TestRunJUnit(t *testing.T) {
+	dir := t.TempDir()
+	for name, src := range map[string]string{"bad.go": swappedSrc, "good.go": "package main\n\nimport \"fmt\"\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-groups", "fmt;time", "-format", "junit", dir}, nil, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit code 1, got %d: %s", code, stderr.String())
+	}
+
+	var report struct {
+		Suites []struct {
+			Tests    int `xml:"tests,attr"`
+			Failures int `xml:"failures,attr"`
+			Cases    []struct {
+				Name    string `xml:"name,attr"`
+				Failure *struct {
+					Type string `xml:"type,attr"`
+					Text string `xml:",chardata"`
+				} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+
+	if err := xml.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Suites) != 1 || report.Suites[0].Tests != 2 || report.Suites[0].Failures != 1 {
+		t.Fatalf("expected a suite of 2 tests and 1 failure, got\n%s", stdout.String())
+	}
+
+	for _, tc := range report.Suites[0].Cases {
+		failed := tc.Failure != nil && tc.Failure.Type == "group-order" && strings.Contains(tc.Failure.Text, "bad.go:6:2")
+		if strings.HasSuffix(tc.Name, "bad.go") != failed {
+			t.Errorf("expected bad.go only to fail with its issue, got %s failing with %+v", tc.Name, tc.Failure)
+		}
+	}
+}
+
+func TestRunCodeClimate(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	// report checks src as sub/main.go of a new git repository, from the directory sub, and returns its single issue
+	// of the rule code
+	report := func(src, code string) map[string]any {
+		t.Helper()
+
+		dir := t.TempDir()
+		if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+			t.Fatalf("git init: %v: %s", err, out)
+		}
+
+		sub := filepath.Join(dir, "sub")
+		if err := os.Mkdir(sub, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(sub, "main.go"), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chdir(sub); err != nil {
+			t.Fatal(err)
+		}
+
+		var stdout, stderr bytes.Buffer
+		args := []string{"-groups", "fmt;time", "-format", "codeclimate", "-explain", "main.go"}
+		if exit := run(args, nil, &stdout, &stderr); exit != 1 {
+			t.Fatalf("expected exit code 1, got %d: %s", exit, stderr.String())
+		}
+
+		var issues []map[string]any
+		if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(issues) != 1 || issues[0]["check_name"] != code {
+			t.Fatalf("expected a %s issue, got\n%s", code, stdout.String())
+		}
+
+		return issues[0]
+	}
+
+	before := report(swappedSrc, "group-order")
+	after := report("// Command main.\n\n"+swappedSrc, "group-order")
+
+	location := after["location"].(map[string]any)
+	if location["path"] != "sub/main.go" {
+		t.Errorf("expected the path relative to the root of the repository, got %v", location["path"])
+	}
+
+	if line := location["lines"].(map[string]any)["begin"]; line != 8.0 {
+		t.Errorf("expected the issue on line 8, got %v", line)
+	}
+
+	if before["fingerprint"] != after["fingerprint"] {
+		t.Errorf("expected the fingerprint to stay the same across checkouts and line shifts, got %v and %v",
+			before["fingerprint"], after["fingerprint"])
+	}
+
+	// the message of the issue names the line of the other declaration
+	decls := "package main\n\nimport \"fmt\"\n\nimport \"time\"\n\nvar _, _ = time.Now, fmt.Println\n"
+	before = report(decls, "multiple-import-decls")
+	after = report("// Command main.\n\n"+decls, "multiple-import-decls")
+
+	if before["description"] == after["description"] {
+		t.Errorf("expected the line shift to change the message, got %v", after["description"])
+	}
+
+	if before["fingerprint"] != after["fingerprint"] {
+		t.Errorf("expected the fingerprint of the multiple-import-decls issue to stay the same across line shifts, "+
+			"got %v and %v", before["fingerprint"], after["fingerprint"])
+	}
+}
+
+func TestRunMessageFormat(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(name, []byte(swappedSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "template",
+			args: []string{"-message-format", "{{.Line}} {{.Code}} {{.Path}} -> {{.Expected}}"},
+			want: "6 group-order fmt -> fmt\n",
+		},
+		{
+			name: "color",
+			args: []string{"-color", "always"},
+			want: "\x1b[1m" + name + ":6:2\x1b[0m: import \x1b[1;31m\"fmt\"\x1b[0m belongs to group \"fmt\" (group 1) " +
+				"but appears after group 2 (\"time\") (\x1b[2mgroup-order\x1b[0m)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"-groups", "fmt;time"}, append(tt.args, name)...)
+			if code := run(args, nil, &stdout, &stderr); code != 1 {
+				t.Fatalf("expected exit code 1, got %d: %s", code, stderr.String())
+			}
+
+			if stdout.String() != tt.want {
+				t.Errorf("expected\n%q\ngot\n%q", tt.want, stdout.String())
+			}
+		})
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-message-format", "{{.Line", name}, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for an invalid template, got %d", code)
+	}
+}
+
+func TestRunSeverities(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(name, []byte(swappedSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{args: []string{"-severities", "group-order=warning"}, want: 0},
+		{args: []string{"-severity", "warning"}, want: 0},
+		{args: []string{"-severity", "warning", "-severities", "group-order=error"}, want: 1},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		args := append([]string{"-groups", "fmt;time", "-format", "json"}, append(tt.args, name)...)
+		if code := run(args, nil, &stdout, &stderr); code != tt.want {
+			t.Errorf("%v: expected exit code %d, got %d: %s", tt.args, tt.want, code, stderr.String())
+		}
+
+		var record struct {
+			Code, Severity string
+		}
+		if err := json.Unmarshal(stdout.Bytes(), &record); err != nil {
+			t.Fatalf("%v: %v", tt.args, err)
+		}
+
+		if wantSeverity := map[int]string{0: "warning", 1: "error"}[tt.want]; record.Severity != wantSeverity {
+			t.Errorf("%v: expected the %s issue of severity %s, got %s", tt.args, record.Code, wantSeverity,
+				record.Severity)
+		}
+	}
+}
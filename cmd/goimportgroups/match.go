@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+// match runs the match subcommand with args, writing to stdout the group each import path of args belongs to, along
+// with the trace of the evaluation of the patterns of the groups, and returns its exit code: 0 if all of them belong to
+// a group, 1 if some do not, 2 on errors.
+func match(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("goimportgroups match", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	cfg := analyzer.DefaultConfig()
+	analyzer.BindFlags(flags, &cfg)
+	configFile := bindConfigFlag(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: goimportgroups match [flags] importpath ...")
+		return 2
+	}
+
+	cfg, err := resolveConfig(cfg, flags, *configFile)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	c, err := analyzer.NewChecker(cfg)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	code := 0
+	for _, path := range flags.Args() {
+		group, trace, err := c.TraceMatch(path)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+
+		result := fmt.Sprintf("%q belongs to group %d", path, group)
+		if group == 0 {
+			result = fmt.Sprintf("%q belongs to no group", path)
+			code = 1
+		}
+
+		if _, err := fmt.Fprintf(stdout, "%s%s\n", trace, result); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+
+	return code
+}
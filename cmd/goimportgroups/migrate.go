@@ -0,0 +1,304 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+// gciSettings are the settings of gci converted by migrate.
+type gciSettings struct {
+	Sections    []string `yaml:"sections"`
+	CustomOrder bool     `yaml:"custom-order"`
+}
+
+// gciFile is a configuration file holding gci settings, either a golangci-lint one, of version 1 or 2, or one of gci
+// alone.
+type gciFile struct {
+	gciSettings `yaml:",inline"`
+
+	LintersSettings struct {
+		Gci gciSettings `yaml:"gci"`
+	} `yaml:"linters-settings"`
+	Formatters struct {
+		Settings struct {
+			Gci gciSettings `yaml:"gci"`
+		} `yaml:"settings"`
+	} `yaml:"formatters"`
+}
+
+// configGroup is a group of the configuration files written by the subcommands.
+type configGroup struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern"`
+	// specificity ranks the groups converted from other linters, which put an import in the most specific group
+	// matching it rather than the first one, 0 for the groups overlapping no other one
+	specificity int
+}
+
+// gciOrder is the rank of the kinds of gci sections in the order gci gives them without custom-order.
+var gciOrder = map[string]int{
+	"standard":    0,
+	"default":     1,
+	"prefix":      2,
+	"blank":       3,
+	"dot":         4,
+	"alias":       5,
+	"localmodule": 6,
+}
+
+// gciSection matches a gci section, a kind along with the arguments of prefix.
+var gciSection = regexp.MustCompile(`^(?i:(standard|default|prefix|blank|dot|alias|localmodule))\s*(?:\((.*)\))?$`)
+
+// migrate runs the migrate subcommand with args, writing to stdout the configuration file equivalent to the one of
+// another linter, and returns its exit code: 0 on success, 2 on errors.
+func migrate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("goimportgroups migrate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	from := flags.String("from", "gci", "linter whose configuration to convert, gci or goimports-reviser")
+	var reviser reviserSettings
+	flags.StringVar(&reviser.projectName, "project-name", "",
+		"-project-name of goimports-reviser, the module of the current directory by default")
+	flags.StringVar(&reviser.companyPrefixes, "company-prefixes", "", "-company-prefixes of goimports-reviser")
+	flags.StringVar(&reviser.importsOrder, "imports-order", "std,general,company,project",
+		"-imports-order of goimports-reviser")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	var groups []configGroup
+	var warnings []string
+	var source string
+	var err error
+	switch *from {
+	case "gci":
+		source = ".golangci.yml"
+		if flags.NArg() > 0 {
+			source = flags.Arg(0)
+		}
+
+		groups, warnings, err = migrateGCI(source)
+		source = "the gci sections of " + source
+	case "goimports-reviser":
+		groups, warnings, err = migrateReviser(reviser)
+		source = "the goimports-reviser settings"
+	default:
+		err = fmt.Errorf("unknown linter %q to migrate from, expected gci or goimports-reviser", *from)
+	}
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	for _, w := range warnings {
+		fmt.Fprintln(stderr, w)
+	}
+
+	data, err := yaml.Marshal(struct {
+		Groups []configGroup `yaml:"groups"`
+	}{groups})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	_, err = fmt.Fprintf(stdout, "# %s converted from %s\n%s", analyzer.ConfigFileName, source, data)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	return 0
+}
+
+// migrateGCI returns the groups equivalent to the gci sections of the configuration file name, along with warnings
+// about the sections left out.
+func migrateGCI(name string) ([]configGroup, []string, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var f gciFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, nil, fmt.Errorf("cannot parse %s: %w", name, err)
+	}
+
+	settings := f.gciSettings
+	for _, s := range []gciSettings{f.LintersSettings.Gci, f.Formatters.Settings.Gci} {
+		if len(settings.Sections) == 0 {
+			settings = s
+		}
+	}
+
+	if len(settings.Sections) == 0 {
+		return nil, nil, fmt.Errorf("%s has no gci sections", name)
+	}
+
+	type section struct {
+		kind, args, text string
+	}
+
+	var sections []section
+	for _, text := range settings.Sections {
+		m := gciSection.FindStringSubmatch(strings.TrimSpace(text))
+		if m == nil {
+			return nil, nil, fmt.Errorf("unknown gci section %q", text)
+		}
+
+		sections = append(sections, section{kind: strings.ToLower(m[1]), args: m[2], text: text})
+	}
+
+	if !settings.CustomOrder {
+		sort.SliceStable(sections, func(i, j int) bool {
+			return gciOrder[sections[i].kind] < gciOrder[sections[j].kind]
+		})
+	}
+
+	// default is left without pattern, as it depends on the patterns of the sections after it
+	var groups []configGroup
+	var warnings []string
+	for _, s := range sections {
+		g := configGroup{Name: s.text}
+		switch s.kind {
+		case "standard":
+			g.Pattern = "std"
+		case "localmodule":
+			g.Pattern, g.specificity = "localmodule", 2
+		case "prefix":
+			g.Pattern, g.specificity = prefixPattern(s.args), 1
+			if g.Pattern == "" {
+				return nil, nil, fmt.Errorf("gci section %q has no prefix", s.text)
+			}
+		case "default":
+		default:
+			warnings = append(warnings, fmt.Sprintf(
+				"gci section %q has no equivalent, its imports stay in the groups of their paths", s.text))
+			continue
+		}
+
+		groups = append(groups, g)
+	}
+
+	narrowGroups(groups)
+
+	if len(groups) == 0 {
+		return nil, nil, errors.New("the gci sections have no equivalent groups")
+	}
+
+	return groups, warnings, nil
+}
+
+// reviserSettings are the settings of goimports-reviser converted by migrate, as passed to it.
+type reviserSettings struct {
+	projectName     string
+	companyPrefixes string
+	importsOrder    string
+}
+
+// migrateReviser returns the groups equivalent to the settings of goimports-reviser, along with warnings about the
+// groups left out.
+func migrateReviser(settings reviserSettings) ([]configGroup, []string, error) {
+	var groups []configGroup
+	var warnings []string
+	for _, name := range strings.Split(settings.importsOrder, ",") {
+		g := configGroup{Name: strings.TrimSpace(name)}
+		switch g.Name {
+		case "std":
+			g.Pattern = "std"
+		case "general":
+		case "company":
+			g.Pattern, g.specificity = prefixPattern(settings.companyPrefixes), 1
+			if g.Pattern == "" {
+				// goimports-reviser leaves the group empty without prefixes
+				continue
+			}
+		case "project":
+			// goimports-reviser checks the project prefix before the company ones
+			g.Pattern, g.specificity = "localmodule", 2
+			if settings.projectName != "" {
+				g.Pattern = prefixPattern(settings.projectName)
+			}
+		case "blanked", "dotted":
+			warnings = append(warnings, fmt.Sprintf(
+				"goimports-reviser group %q has no equivalent, its imports stay in the groups of their paths", g.Name))
+			continue
+		default:
+			return nil, nil, fmt.Errorf("unknown goimports-reviser group %q", g.Name)
+		}
+
+		groups = append(groups, g)
+	}
+
+	narrowGroups(groups)
+
+	if len(groups) == 0 {
+		return nil, nil, errors.New("the goimports-reviser groups have no equivalent groups")
+	}
+
+	return groups, warnings, nil
+}
+
+// prefixPattern returns the pattern matching the import paths starting with one of the comma separated prefixes, or
+// an empty string if there are none.
+func prefixPattern(list string) string {
+	var prefixes []string
+	for _, p := range strings.Split(list, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			prefixes = append(prefixes, escapePattern(regexp.QuoteMeta(p))+".*")
+		}
+	}
+
+	return strings.Join(prefixes, " || ")
+}
+
+// narrowGroups narrows the patterns of groups to the imports of no more specific group after them, as the first group
+// matching an import is the one it belongs to. The groups without pattern, the default groups of the other linters,
+// match the imports of no group after them.
+func narrowGroups(groups []configGroup) {
+	patterns := make([]string, len(groups))
+	for i, g := range groups {
+		patterns[i] = g.Pattern
+	}
+
+	for i := range groups {
+		var later []string
+		for j, g := range groups[i+1:] {
+			if patterns[i+1+j] != "" && (patterns[i] == "" ||
+				groups[i].specificity > 0 && g.specificity > groups[i].specificity) {
+				later = append(later, patterns[i+1+j])
+			}
+		}
+
+		pattern := patterns[i]
+		switch {
+		case pattern == "":
+			pattern = ".*"
+		case len(later) > 0 && strings.Contains(pattern, " || "):
+			pattern = "(" + pattern + ")"
+		}
+
+		if len(later) > 0 {
+			pattern += " && !(" + strings.Join(later, " || ") + ")"
+		}
+
+		groups[i].Pattern = pattern
+	}
+}
+
+// escapePattern escapes the separators of the group expressions in the regex pattern.
+func escapePattern(pattern string) string {
+	return strings.NewReplacer(",", `\,`, ":", `\:`, ";", `\;`).Replace(pattern)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"go/token"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+// The subset of SARIF 2.1.0 written by the command, see
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+
+	sarifRun struct {
+		Tool               sarifTool                        `json:"tool"`
+		OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+		Results            []sarifResult                    `json:"results"`
+	}
+
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+
+	sarifRule struct {
+		ID      string `json:"id"`
+		HelpURI string `json:"helpUri,omitempty"`
+	}
+
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+		Fixes     []sarifFix      `json:"fixes,omitempty"`
+	}
+
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           sarifRegion           `json:"region"`
+	}
+
+	sarifArtifactLocation struct {
+		URI       string `json:"uri"`
+		URIBaseID string `json:"uriBaseId,omitempty"`
+	}
+
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+		EndLine     int `json:"endLine"`
+		EndColumn   int `json:"endColumn"`
+	}
+
+	sarifFix struct {
+		Description     sarifMessage          `json:"description"`
+		ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+	}
+
+	sarifArtifactChange struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Replacements     []sarifReplacement    `json:"replacements"`
+	}
+
+	sarifReplacement struct {
+		DeletedRegion   sarifRegion  `json:"deletedRegion"`
+		InsertedContent sarifMessage `json:"insertedContent"`
+	}
+)
+
+// sarifSourceRoot is the base of the URIs of the files below the root of the sources, as GitHub code scanning
+// expects it.
+const sarifSourceRoot = "%SRCROOT%"
+
+// sarifFormatter writes a single SARIF log of all the issues once they are all known, for GitHub code scanning and
+// the other SARIF consumers. The files are located relative to root, the root of the git repository, the ones
+// outside of it with file URIs.
+type sarifFormatter struct {
+	w       io.Writer
+	root    string
+	docsURL string
+	rules   []sarifRule
+	seen    map[string]bool
+	results []sarifResult
+}
+
+func (f *sarifFormatter) add(name string, issues []analyzer.Issue) error {
+	location, err := f.artifactLocation(name)
+	if err != nil {
+		return err
+	}
+
+	for _, iss := range issues {
+		if !f.seen[iss.Code] {
+			if f.seen == nil {
+				f.seen = make(map[string]bool)
+			}
+
+			f.seen[iss.Code] = true
+			f.rules = append(f.rules, sarifRule{ID: iss.Code, HelpURI: f.docsURL + "#" + iss.Code})
+		}
+
+		result := sarifResult{
+			RuleID:  iss.Code,
+			Level:   iss.Severity,
+			Message: sarifMessage{Text: iss.Message},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: location,
+				Region:           newSarifRegion(iss.Pos, iss.End),
+			}}},
+		}
+
+		for _, fix := range iss.Fixes {
+			change := sarifArtifactChange{ArtifactLocation: location}
+			for _, e := range fix.Edits {
+				change.Replacements = append(change.Replacements, sarifReplacement{
+					DeletedRegion:   newSarifRegion(e.Pos, e.End),
+					InsertedContent: sarifMessage{Text: string(e.NewText)},
+				})
+			}
+
+			result.Fixes = append(result.Fixes, sarifFix{
+				Description:     sarifMessage{Text: fix.Message},
+				ArtifactChanges: []sarifArtifactChange{change},
+			})
+		}
+
+		f.results = append(f.results, result)
+	}
+
+	return nil
+}
+
+// artifactLocation returns the location of the file name, relative to the source root if below it.
+func (f *sarifFormatter) artifactLocation(name string) (sarifArtifactLocation, error) {
+	path, err := rootRelative(f.root, name)
+	if err != nil {
+		return sarifArtifactLocation{}, err
+	}
+
+	if filepath.IsAbs(filepath.FromSlash(path)) {
+		return sarifArtifactLocation{URI: fileURI(path)}, nil
+	}
+
+	return sarifArtifactLocation{URI: (&url.URL{Path: path}).String(), URIBaseID: sarifSourceRoot}, nil
+}
+
+// fileURI returns the file URI of the absolute path with forward slashes.
+func fileURI(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // a path starting with a volume name
+	}
+
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+func (f *sarifFormatter) flush() error {
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "goimportgroups",
+				InformationURI: "https://github.com/kmirzavaziri/goimportgroups",
+				Rules:          append([]sarifRule{}, f.rules...),
+			}},
+			OriginalURIBaseIDs: map[string]sarifArtifactLocation{
+				sarifSourceRoot: {URI: fileURI(strings.TrimSuffix(filepath.ToSlash(f.root), "/") + "/")},
+			},
+			Results: append([]sarifResult{}, f.results...),
+		}},
+	}
+
+	enc := json.NewEncoder(f.w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(log)
+}
+
+func newSarifRegion(pos, end token.Position) sarifRegion {
+	return sarifRegion{StartLine: pos.Line, StartColumn: pos.Column, EndLine: end.Line, EndColumn: end.Column}
+}
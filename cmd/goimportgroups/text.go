@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+// defaultMessageFormat is the template of the lines written per issue by textFormatter, like go vet.
+const defaultMessageFormat = "{{.Pos}}: {{.Message}} ({{.Code}})"
+
+// The ANSI escape sequences of the colors of the text output.
+const (
+	colorReset  = "\x1b[0m"
+	colorBold   = "\x1b[1m"
+	colorFaint  = "\x1b[2m"
+	colorImport = "\x1b[1;31m"
+)
+
+// textIssue is the data of the template of textFormatter per issue, its strings colored with color.
+type textIssue struct {
+	// Pos is the position of the issue as file:line:column.
+	Pos      string
+	File     string
+	Line     int
+	Column   int
+	Code     string
+	Severity string
+	Message  string
+	Path     string
+	Expected string
+}
+
+// textFormatter writes a line per issue, like go vet by default, executing the template of -message-format.
+type textFormatter struct {
+	w     io.Writer
+	tmpl  *template.Template
+	color bool
+}
+
+// newTextFormatter returns the text formatter writing to w with the template messageFormat, or the default one if
+// empty, highlighting the positions, rules and offending imports with ANSI colors if color is set.
+func newTextFormatter(w io.Writer, messageFormat string, color bool) (*textFormatter, error) {
+	if messageFormat == "" {
+		messageFormat = defaultMessageFormat
+	}
+
+	tmpl, err := template.New("message-format").Option("missingkey=error").Parse(messageFormat)
+	if err != nil {
+		return nil, fmt.Errorf("parsing -message-format: %w", err)
+	}
+
+	return &textFormatter{w: w, tmpl: tmpl, color: color}, nil
+}
+
+func (f *textFormatter) add(name string, issues []analyzer.Issue) error {
+	for _, iss := range issues {
+		data := textIssue{
+			Pos:      f.paint(colorBold, iss.Pos.String()),
+			File:     f.paint(colorBold, iss.Pos.Filename),
+			Line:     iss.Pos.Line,
+			Column:   iss.Pos.Column,
+			Code:     f.paint(colorFaint, iss.Code),
+			Severity: iss.Severity,
+			Message:  iss.Message,
+			Path:     f.paint(colorImport, iss.Path),
+			Expected: iss.Expected,
+		}
+
+		if f.color && iss.Path != "" {
+			quoted := strconv.Quote(iss.Path)
+			data.Message = strings.Replace(iss.Message, quoted, f.paint(colorImport, quoted), 1)
+		}
+
+		var line strings.Builder
+		if err := f.tmpl.Execute(&line, data); err != nil {
+			return fmt.Errorf("executing -message-format for %s: %w", name, err)
+		}
+
+		if _, err := fmt.Fprintln(f.w, strings.TrimSuffix(line.String(), "\n")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *textFormatter) flush() error {
+	return nil
+}
+
+// paint returns s wrapped in the escape sequence of color if the formatter colors its output.
+func (f *textFormatter) paint(color, s string) string {
+	if !f.color || s == "" {
+		return s
+	}
+
+	return color + s + colorReset
+}
+
+// useColor reports whether to color the output written to w for the -color flag value mode, auto coloring it for
+// terminals only, unless NO_COLOR is set or TERM is dumb.
+func useColor(mode string, w io.Writer) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+	default:
+		return false, fmt.Errorf("unknown color mode %q, expected auto, always or never", mode)
+	}
+
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false, nil
+	}
+
+	file, ok := w.(*os.File)
+	if !ok {
+		return false, nil
+	}
+
+	info, err := file.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+}
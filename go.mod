@@ -1,10 +1,11 @@
 module github.com/kmirzavaziri/goimportgroups
 
-go 1.20
-
-require golang.org/x/tools v0.12.0
+go 1.21
 
 require (
-	golang.org/x/mod v0.12.0 // indirect
-	golang.org/x/sys v0.11.0 // indirect
+	golang.org/x/mod v0.17.0
+	golang.org/x/tools v0.21.0
+	gopkg.in/yaml.v3 v3.0.1
 )
+
+require golang.org/x/sync v0.7.0 // indirect
@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// findRedundantAliases reports the imports aliased to the name of the package they import, which they would be known
+// by anyway, suggesting to remove the alias.
+func findRedundantAliases(tokFile *token.File, decls []*ast.GenDecl, packageName func(path string) string) []issue {
+	var issues []issue
+	for _, decl := range decls {
+		for _, s := range decl.Specs {
+			spec := s.(*ast.ImportSpec)
+			if spec.Name == nil {
+				continue
+			}
+
+			path := importPath(spec)
+			if spec.Name.Name != packageName(path) {
+				continue
+			}
+
+			iss := newIssue(tokFile, spec, codeRedundantAlias, messageArgs{Path: path, Name: spec.Name.Name})
+			iss.fixes = []fix{{
+				message: msgFixRedundantAlias,
+				edits: []edit{{
+					pos: tokFile.Offset(spec.Name.Pos()),
+					end: tokFile.Offset(spec.Path.Pos()),
+				}},
+			}}
+
+			issues = append(issues, iss)
+		}
+	}
+
+	return issues
+}
+
+// findDuplicateImports reports the imports of a path already imported under a different name earlier in the file.
+func findDuplicateImports(tokFile *token.File, decls []*ast.GenDecl, packageName func(path string) string) []issue {
+	first := make(map[string]*ast.ImportSpec)
+
+	var issues []issue
+	for _, decl := range decls {
+		for _, s := range decl.Specs {
+			spec := s.(*ast.ImportSpec)
+			path := importPath(spec)
+
+			kept, ok := first[path]
+			if !ok {
+				first[path] = spec
+				continue
+			}
+
+			name, keptName := localName(spec, packageName), localName(kept, packageName)
+			if name == keptName {
+				continue
+			}
+
+			issues = append(issues, newIssue(tokFile, spec, codeDuplicateImport, messageArgs{
+				Path:  path,
+				Name:  name,
+				Other: keptName,
+				Line:  tokFile.Line(kept.Pos()),
+			}))
+		}
+	}
+
+	return issues
+}
+
+// localName returns the name spec makes the imported package available as in the file.
+func localName(spec *ast.ImportSpec, packageName func(path string) string) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+
+	return packageName(importPath(spec))
+}
@@ -1,237 +1,662 @@
 package analyzer
 
 import (
+	"bytes"
+	"errors"
 	"flag"
 	"fmt"
 	"go/ast"
-	"go/parser"
 	"go/token"
 	"golang.org/x/tools/go/analysis"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
-	"regexp"
+	"reflect"
 	"strings"
 )
 
-var (
-	commentRegex = regexp.MustCompile(`//.*|/\*.*?\*/`)
-)
+// settings holds the values of the flags of an analyzer, each analyzer having its own.
+type settings struct {
+	flags  flag.FlagSet
+	config Config
 
-var (
-	flagSet flag.FlagSet
-	groups  string
-)
+	maxIssues   int
+	readRetries int
+	configFile  string
+
+	verbose     bool
+	veryVerbose bool
+}
+
+// newSettings returns the settings of a new analyzer, with its flags defined.
+func newSettings() *settings {
+	s := &settings{config: DefaultConfig()}
 
-func init() {
-	flagSet.StringVar(
-		&groups,
+	BindFlags(&s.flags, &s.config)
+
+	s.flags.IntVar(
+		&s.maxIssues,
+		"max-issues",
+		0,
+		"maximum number of issues reported by the whole run, the first one left out notes the limit (0 means no limit)",
+	)
+	s.flags.IntVar(
+		&s.readRetries,
+		"read-retries",
+		0,
+		"number of times a failed read of a source file is retried, waiting longer each time, for networked and "+
+			"virtual filesystems",
+	)
+	s.flags.StringVar(
+		&s.configFile,
+		"config",
+		"",
+		"YAML configuration file, by default "+ConfigFileName+" in the directory of the package or the closest of its "+
+			"parents, if any (flags set explicitly take precedence)",
+	)
+	s.flags.BoolVar(&s.verbose, "v", false, "log configuration resolution and checked packages to stderr")
+	s.flags.BoolVar(&s.veryVerbose, "vv", false, "like -v, additionally logging file selection and skip decisions")
+
+	// the drivers set the values of the flags of analyzers directly, without the flag set recording it
+	s.flags.VisitAll(func(f *flag.Flag) {
+		f.Value = &setValue{Value: f.Value}
+	})
+
+	return s
+}
+
+// BindFlags defines on flags the flags of the analyzer setting the fields of cfg, using their current values as defaults,
+// so other commands can accept the same configuration.
+func BindFlags(flags *flag.FlagSet, cfg *Config) {
+	flags.StringVar(
+		&cfg.Groups,
 		"groups",
-		".*",
-		"left associative boolean expression of import path regex patterns",
+		cfg.Groups,
+		"semicolon separated boolean expressions of import path regex patterns, std matching the standard library",
+	)
+	flags.StringVar(
+		&cfg.Preset,
+		"preset",
+		cfg.Preset,
+		"built-in layout of groups used instead of -groups: std-thirdparty, std-thirdparty-local or "+
+			"std-thirdparty-org-local",
+	)
+	flags.StringVar(
+		&cfg.DocsURL,
+		"docs-url",
+		cfg.DocsURL,
+		"base URL of the rule documentation, the rule code is appended as a fragment",
+	)
+	flags.IntVar(
+		&cfg.MaxIssuesPerFile,
+		"max-issues-per-file",
+		cfg.MaxIssuesPerFile,
+		"maximum number of issues reported per file, the remainder is summarized in one diagnostic (0 means no limit)",
+	)
+	flags.BoolVar(
+		&cfg.CollapseIdentical,
+		"collapse-identical",
+		cfg.CollapseIdentical,
+		"report issues with identical messages only once per file",
+	)
+	flags.StringVar(
+		&cfg.Disable,
+		"disable",
+		cfg.Disable,
+		"comma separated rule codes not to report, \"info\" disables all informational rules",
+	)
+	flags.StringVar(
+		&cfg.Severity,
+		"severity",
+		cfg.Severity,
+		"severity of the issues, error or warning, the command exiting with 1 for errors only",
+	)
+	flags.StringVar(
+		&cfg.Severities,
+		"severities",
+		cfg.Severities,
+		"comma separated code=severity pairs overriding -severity for the rules of the codes, like "+
+			"unsorted-import=warning",
+	)
+	flags.IntVar(
+		&cfg.Preview,
+		"preview",
+		cfg.Preview,
+		"number of lines of the expected import block, starting at the first difference, to include in the first "+
+			"diagnostic of a file (0 disables the preview)",
+	)
+	flags.BoolVar(
+		&cfg.Explain,
+		"explain",
+		cfg.Explain,
+		"include in the first issue of a file the group each block of its imports was matched to",
+	)
+	flags.StringVar(
+		&cfg.Messages,
+		"messages",
+		cfg.Messages,
+		"JSON file mapping message keys to text/template strings overriding the default messages",
+	)
+	flags.StringVar(
+		&cfg.Sort,
+		"sort",
+		cfg.Sort,
+		"order of the imports within a group of rendered import blocks: none, path, case-insensitive, domain or "+
+			"std-first",
+	)
+	flags.BoolVar(
+		&cfg.Sorted,
+		"sorted",
+		cfg.Sorted,
+		"report imports out of the -sort order within their block, sorting by path if -sort is none",
+	)
+	flags.BoolVar(
+		&cfg.SinkBlankDot,
+		"sink-blank-dot",
+		cfg.SinkBlankDot,
+		"move blank and dot imports to the end of their group in rendered import blocks",
+	)
+	flags.BoolVar(
+		&cfg.AlignAliases,
+		"align-aliases",
+		cfg.AlignAliases,
+		"align the aliases of each group in a column in rendered import blocks",
+	)
+	flags.BoolVar(
+		&cfg.NormalizeQuotes,
+		"normalize-quotes",
+		cfg.NormalizeQuotes,
+		"render all import paths as double-quoted strings in rendered import blocks",
+	)
+	flags.BoolVar(
+		&cfg.RemoveRedundantAliases,
+		"remove-redundant-aliases",
+		cfg.RemoveRedundantAliases,
+		"drop aliases equal to the package name in rendered import blocks",
+	)
+	flags.StringVar(
+		&cfg.Comments,
+		"comments",
+		cfg.Comments,
+		"semicolon separated regex patterns, one per group, the trailing comments of the imports of the group must "+
+			"match (an empty pattern leaves the group unchecked)",
+	)
+	flags.BoolVar(
+		&cfg.ImportPosition,
+		"import-position",
+		cfg.ImportPosition,
+		"report import declarations separated from the package clause by more than a blank line and their doc comment",
+	)
+	flags.StringVar(
+		&cfg.Policies,
+		"policies",
+		cfg.Policies,
+		"comma separated names of registered policy packs applied over the other flags",
+	)
+	flags.Var(
+		listValue{&cfg.Exclude},
+		"exclude",
+		"semicolon separated regex, or glob with -pattern-syntax glob, patterns of the paths of the files not to check, "+
+			"may be repeated",
+	)
+	flags.BoolVar(
+		&cfg.IncludeGenerated,
+		"include-generated",
+		cfg.IncludeGenerated,
+		"check generated files, the ones with a \"// Code generated ... DO NOT EDIT.\" comment, too",
+	)
+	flags.BoolVar(
+		&cfg.ReportEmptyDecls,
+		"report-empty-decls",
+		cfg.ReportEmptyDecls,
+		"report import declarations without imports, like import (), with a fix deleting them",
+	)
+	flags.BoolVar(
+		&cfg.ReportSplitGroups,
+		"report-split-groups",
+		cfg.ReportSplitGroups,
+		"report blocks of imports of the same group as the block before them, instead of a single block per group",
+	)
+	flags.BoolVar(
+		&cfg.DeclGroups,
+		"decl-groups",
+		cfg.DeclGroups,
+		"check each import declaration as a block of a single group, instead of blocks separated by blank lines",
+	)
+	flags.BoolVar(
+		&cfg.RelaxedOrder,
+		"relaxed-order",
+		cfg.RelaxedOrder,
+		"let the blocks of imports come in any order of their groups, as long as each block belongs to a single group",
+	)
+	flags.StringVar(
+		&cfg.GroupNames,
+		"group-names",
+		cfg.GroupNames,
+		"semicolon separated names of the groups, used instead of their patterns in messages and header comments",
+	)
+	flags.StringVar(
+		&cfg.RequiredGroups,
+		"required-groups",
+		cfg.RequiredGroups,
+		"comma separated numbers or names of the groups every import declaration has to import packages of",
+	)
+	flags.BoolVar(
+		&cfg.HeaderComments,
+		"header-comments",
+		cfg.HeaderComments,
+		"head each block of imports rendered by the fixes with a comment naming its group",
+	)
+	flags.StringVar(
+		&cfg.PatternSyntax,
+		"pattern-syntax",
+		cfg.PatternSyntax,
+		"syntax of the patterns of the groups, regex or glob, like github.com/org/**",
+	)
+	flags.StringVar(
+		&cfg.LocalModule,
+		"local-module",
+		cfg.LocalModule,
+		"module path matched by the localmodule keyword, read from the nearest go.mod by default",
+	)
+	flags.StringVar(
+		&cfg.GoVersion,
+		"go-version",
+		cfg.GoVersion,
+		"Go version of the checked files, like go1.22, selecting the packages matched by the std keyword, read from "+
+			"the go directive of the nearest go.mod by default",
 	)
 }
 
+// NewAnalyzer returns the analyzer, reading the sources it checks with the ReadFile of the pass when the driver sets
+// it, for the drivers to supply the unsaved content of editors, and from the disk otherwise.
 func NewAnalyzer() *analysis.Analyzer {
-	return &analysis.Analyzer{
-		Name:  "goimportgroups",
-		Doc:   "Checks if go imports are separated into user-defined groups.",
-		Run:   run,
-		Flags: flagSet,
-	}
+	return newAnalyzer(os.ReadFile, true)
 }
 
-func run(pass *analysis.Pass) (interface{}, error) {
-	fileNames, poses := getFileNamesAndPoses(pass)
+// NewAnalyzerFS returns an analyzer like NewAnalyzer that reads the sources it checks from fsys instead of the disk,
+// e.g. to check the unsaved content of an editor. The names of the files of a pass are looked up in fsys with the
+// leading separator removed.
+func NewAnalyzerFS(fsys fs.FS) *analysis.Analyzer {
+	return newAnalyzer(readFromFS(fsys), false)
+}
 
-	for i, filename := range fileNames {
-		pos, msg, err := check(filename)
-		if err != nil {
-			return nil, err
-		}
+// NewAnalyzerHermetic returns an analyzer like NewAnalyzer that never reads the disk, for sandboxed drivers like the
+// nogo of Bazel, where the names of the files of a pass do not resolve. It checks the syntax trees of the pass only,
+// leaving out the fixes and the checks needing the source text, the commented-out imports and the position of the
+// imports, and skips the files generated by cgo, unless the ReadFile of the pass supplies their text. The
+// configuration file is read only when passed with -config, and the localmodule keyword matches only the
+// -local-module.
+func NewAnalyzerHermetic() *analysis.Analyzer {
+	return newAnalyzer(nil, true)
+}
 
-		if msg != "" {
-			pass.Reportf(poses[i]+token.Pos(pos), msg)
-		}
-	}
+// newAnalyzer returns the analyzer reading the sources with readFile, or checking the passes only if nil, unless
+// fromPass is set and the pass reads them.
+func newAnalyzer(readFile func(name string) ([]byte, error), fromPass bool) *analysis.Analyzer {
+	s := newSettings()
+	limiter := &globalLimiter{max: &s.maxIssues}
+
+	return &analysis.Analyzer{
+		Name: "goimportgroups",
+		Doc:  "Checks if go imports are separated into user-defined groups.",
+		URL:  "https://github.com/kmirzavaziri/goimportgroups",
+		// the result lets other analyzers use the classification of the imports
+		ResultType: reflect.TypeOf((*Imports)(nil)),
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			if fromPass && pass.ReadFile != nil {
+				return run(pass, s, limiter, readFile != nil, pass.ReadFile)
+			}
 
-	return nil, nil
+			return run(pass, s, limiter, readFile != nil, readFile)
+		},
+		Flags: s.flags,
+	}
 }
 
-func check(filename string) (int, string, error) {
-	groupPatterns := strings.Split(groups, ";")
+// run checks the files of pass with the settings s, read with readFile, or without their text if nil. The
+// configuration file and the go.mod are looked up next to the files if search is set.
+func run(
+	pass *analysis.Pass, s *settings, limiter *globalLimiter, search bool, readFile func(name string) ([]byte, error),
+) (interface{}, error) {
+	logger := newLogger(s.verbose, s.veryVerbose).With("package", pass.Pkg.Path())
+	files := getFiles(pass, logger)
 
-	fileBytes, err := os.ReadFile(filename)
+	cfg, err := resolveConfig(s, files, search, logger)
 	if err != nil {
-		return 0, "", err
+		return nil, err
 	}
 
-	fileNode, err := parser.ParseFile(token.NewFileSet(), filename, fileBytes, parser.ImportsOnly)
+	logger.Info(
+		"resolved configuration",
+		"groups", cfg.Groups,
+		"preset", cfg.Preset,
+		"docs_url", cfg.DocsURL,
+		"max_issues_per_file", cfg.MaxIssuesPerFile,
+		"max_issues", s.maxIssues,
+		"read_retries", s.readRetries,
+		"config", s.configFile,
+		"local_module", cfg.LocalModule,
+		"go_version", cfg.GoVersion,
+		"collapse_identical", cfg.CollapseIdentical,
+		"disable", cfg.Disable,
+		"severity", cfg.Severity,
+		"severities", cfg.Severities,
+		"preview", cfg.Preview,
+		"explain", cfg.Explain,
+		"messages", cfg.Messages,
+		"sort", cfg.Sort,
+		"sorted", cfg.Sorted,
+		"sink_blank_dot", cfg.SinkBlankDot,
+		"align_aliases", cfg.AlignAliases,
+		"normalize_quotes", cfg.NormalizeQuotes,
+		"remove_redundant_aliases", cfg.RemoveRedundantAliases,
+		"comments", cfg.Comments,
+		"import_position", cfg.ImportPosition,
+		"policies", cfg.Policies,
+		"exclude", cfg.Exclude,
+		"include_generated", cfg.IncludeGenerated,
+		"report_empty_decls", cfg.ReportEmptyDecls,
+		"report_split_groups", cfg.ReportSplitGroups,
+		"relaxed_order", cfg.RelaxedOrder,
+		"decl_groups", cfg.DeclGroups,
+		"group_names", cfg.GroupNames,
+		"required_groups", cfg.RequiredGroups,
+		"header_comments", cfg.HeaderComments,
+		"pattern_syntax", cfg.PatternSyntax,
+	)
+
+	c, err := newChecker(cfg, logger)
 	if err != nil {
-		fmt.Println("Error parsing:", err)
-		return 0, "", err
+		return nil, err
 	}
 
-	importsStart, importsEnd, errorMessage := getImports(fileNode)
-	if errorMessage != "" {
-		return importsStart, fmt.Sprintf("File is not goimportgroups-ed: %s", errorMessage), nil
+	if readFile != nil {
+		readFile = retryReads(readFile, s.readRetries)
 	}
 
-	if importsStart == importsEnd {
-		return 0, "", nil
-	}
+	var errs []error
+	reported := 0
+	result := &Imports{}
+	for _, file := range files {
+		n, groups, err := checkFile(pass, c, limiter, file, readFile, logger)
+		if err != nil {
+			errs = append(errs, err)
+		}
 
-	src := string(fileBytes)
+		reported += n
+		if groups != nil {
+			result.Files = append(result.Files, FileImports{Name: file.name, Groups: groups})
+		}
+	}
 
-	importsSrc := src[importsStart-1 : importsEnd-1]
-	importsSrc = strings.TrimSpace(importsSrc)
-	importsSrc = strings.TrimPrefix(importsSrc, "import")
-	importsSrc = strings.TrimSpace(importsSrc)
-	importsSrc = strings.TrimPrefix(importsSrc, "(")
-	importsSrc = strings.TrimSuffix(importsSrc, ")")
-	importsSrc = strings.TrimSpace(importsSrc)
-	importsSrc = commentRegex.ReplaceAllString(importsSrc, "")
+	logger.Info("checked package", "files", len(files), "issues", reported, "errors", len(errs))
 
-	importLines := strings.Split(importsSrc, "\n")
+	return result, errors.Join(errs...)
+}
 
-	var groups [][]string
-	var currGroup []string
+// resolveConfig returns the configuration of the flags of s, over the one of the configuration file passed with
+// -config or found next to the first of files, if any. The local module and the Go version are read from the go.mod
+// next to the first of files unless set. Neither is looked up next to the files unless search is set.
+func resolveConfig(s *settings, files []passFile, search bool, logger *slog.Logger) (Config, error) {
+	name := s.configFile
+	if name == "" && len(files) > 0 && search {
+		var err error
+		name, err = FindConfigFile(filepath.Dir(files[0].name))
+		if err != nil {
+			return Config{}, err
+		}
+	}
 
-	for _, line := range importLines {
-		line = strings.TrimSpace(line)
+	cfg := s.config
+	if name != "" {
+		logger.Info("loading configuration file", "file", name)
 
-		if line == "" {
-			groups = append(groups, currGroup)
-			currGroup = []string{}
-			continue
+		fileCfg, err := LoadConfigFile(name, DefaultConfig())
+		if err != nil {
+			return Config{}, err
 		}
 
-		importPath := ""
+		cfg = OverrideConfig(fileCfg, &s.flags)
+	}
 
-		if strings.HasPrefix(line, "\"") {
-			importPath = strings.TrimSuffix(line[1:], "\"")
-		} else {
-			_, importPath, _ = strings.Cut(line, " ")
-			importPath = strings.TrimSpace(importPath)
+	if cfg.LocalModule == "" && len(files) > 0 && search {
+		var err error
+		cfg.LocalModule, err = FindModulePath(filepath.Dir(files[0].name))
+		if err != nil {
+			return Config{}, err
 		}
+	}
 
-		currGroup = append(currGroup, importPath)
+	if cfg.GoVersion == "" && len(files) > 0 && search {
+		var err error
+		cfg.GoVersion, err = FindGoVersion(filepath.Dir(files[0].name))
+		if err != nil {
+			return Config{}, err
+		}
 	}
 
-	groups = append(groups, currGroup)
-	currGroup = []string{}
+	return cfg, nil
+}
 
-	currPatternI := 0
-	for _, g := range groups {
-		if len(g) == 0 {
-			continue
+// checkFile reports the issues found in file and returns their number, along with the classified imports of the file.
+// Without readFile, or if readFile does not find the file, the syntax tree of the pass is checked without the text of
+// the file.
+func checkFile(
+	pass *analysis.Pass,
+	c *Checker,
+	limiter *globalLimiter,
+	file passFile,
+	readFile func(name string) ([]byte, error),
+	logger *slog.Logger,
+) (int, []Group, error) {
+	logger.Debug("checking file", "file", file.name)
+
+	fset, node, src := pass.Fset, file.node, []byte(nil)
+	if readFile != nil {
+		var err error
+		fset, node, src, err = readSource(pass.Fset, file, readFile, logger)
+		switch {
+		case errors.Is(err, fs.ErrNotExist) && file.tokFile.Name() == file.name:
+			// sandboxed drivers, like nogo in Bazel, may not expose the files of the pass, check the syntax tree alone
+			logger.Debug("skipping text checks of file not found", "file", file.name)
+			fset, node, src = pass.Fset, file.node, nil
+		case errors.Is(err, fs.ErrNotExist):
+			logger.Debug("skipping file generated from one not found", "file", file.name)
+			return 0, nil, nil
+		case err != nil:
+			return 0, nil, err
 		}
+	} else if file.tokFile.Name() != file.name {
+		logger.Debug("skipping file generated from one not read", "file", file.name)
+		return 0, nil, nil
+	}
 
-		for currPatternI < len(groupPatterns) { // ignoring empty groups
-			matches, err := match(g[0], groupPatterns[currPatternI])
-			if err != nil {
-				return 0, "", err
-			}
-
-			if matches {
-				break
-			}
+	issues, err := c.checkNode(fset, node, src, packageNames(pass))
+	if err != nil {
+		return 0, nil, err
+	}
 
-			currPatternI++
-		}
+	groups, err := c.classify(fset, node)
+	if err != nil {
+		return 0, nil, err
+	}
 
-		if currPatternI >= len(groupPatterns) {
-			return importsStart, "File is not goimportgroups-ed", nil
-		}
+	issues = limiter.limit(c, issues)
 
-		for _, imp := range g {
-			matches, err := match(imp, groupPatterns[currPatternI])
-			if err != nil {
-				return 0, "", err
-			}
+	logger.Debug("checked file", "file", file.name, "issues", len(issues))
 
-			if !matches {
-				return importsStart, "File is not goimportgroups-ed", nil
-			}
+	for i, iss := range issues {
+		msg, err := c.messages.message(iss)
+		if err != nil {
+			return i, groups, err
 		}
-	}
-
-	return 0, "", nil
-}
 
-func getImports(node *ast.File) (int, int, string) {
-	start := 0
-	end := 0
-	found := false
-	for _, decl := range node.Decls {
-		genDecl, ok := decl.(*ast.GenDecl)
-		if !ok || genDecl.Tok != token.IMPORT {
-			continue
+		fixes, err := suggestedFixes(c, file.tokFile, iss)
+		if err != nil {
+			return i, groups, err
 		}
 
-		if found {
-			return int(genDecl.Pos()), int(genDecl.End()), "cannot have two import sections"
+		fixes, err = typeSafeFixes(c, pass, file.node, filePos(file.tokFile, iss.pos), iss, fixes)
+		if err != nil {
+			return i, groups, err
 		}
 
-		start = int(genDecl.Pos())
-		end = int(genDecl.End())
-		found = true
+		pass.Report(analysis.Diagnostic{
+			Pos:            filePos(file.tokFile, iss.pos),
+			End:            filePos(file.tokFile, iss.end),
+			Category:       iss.code,
+			Message:        msg,
+			URL:            c.ruleURL(iss.code),
+			SuggestedFixes: fixes,
+		})
 	}
 
-	return start, end, ""
+	return len(issues), groups, nil
 }
 
-func match(s string, patterns string) (bool, error) {
-	lastAnd := strings.LastIndex(patterns, ",")
-	lastOr := strings.LastIndex(patterns, ":")
+// readSource reads the source of file with readFile and returns it along with the syntax tree to check and its file
+// set, which are the ones of the pass unless it holds a file generated from the one read. The source is nil if the
+// pass holds other content than the one read.
+func readSource(
+	fset *token.FileSet, file passFile, readFile func(name string) ([]byte, error), logger *slog.Logger,
+) (*token.FileSet, *ast.File, []byte, error) {
+	src, err := readFile(file.name)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %w", ErrIO, err)
+	}
+
+	if file.tokFile.Name() != file.name {
+		// the pass holds a file generated from the one on disk, like cgo does, check the latter instead
+		fset = token.NewFileSet()
 
-	if lastAnd > lastOr {
-		l, err := match(s, patterns[:lastAnd])
+		node, err := parseImports(fset, file.name, src)
 		if err != nil {
-			return false, err
+			return nil, nil, nil, err
 		}
 
-		r, err := match(s, patterns[lastAnd+1:])
-		if err != nil {
-			return false, err
-		}
+		return fset, node, src, nil
+	}
 
-		return l && r, nil
+	if !sameLines(file.tokFile, src) {
+		// the pass holds other content than the disk, like an overlay of an editor, leave the text out
+		logger.Debug("skipping text checks of file changed on disk", "file", file.name)
+		return fset, file.node, nil, nil
 	}
 
-	if lastOr > lastAnd {
-		l, err := match(s, patterns[:lastOr])
+	return fset, file.node, src, nil
+}
+
+func suggestedFixes(c *Checker, file *token.File, iss issue) ([]analysis.SuggestedFix, error) {
+	var fixes []analysis.SuggestedFix
+	for _, f := range iss.fixes {
+		msg, err := c.messages.fixMessage(f, iss.args)
 		if err != nil {
-			return false, err
+			return nil, err
 		}
 
-		r, err := match(s, patterns[lastOr+1:])
-		if err != nil {
-			return false, err
+		fix := analysis.SuggestedFix{Message: msg}
+		for _, e := range f.edits {
+			fix.TextEdits = append(fix.TextEdits, analysis.TextEdit{
+				Pos:     filePos(file, e.pos),
+				End:     filePos(file, e.end),
+				NewText: []byte(e.newText),
+			})
 		}
 
-		return l || r, nil
+		fixes = append(fixes, fix)
 	}
 
-	r, err := regexp.MatchString(fmt.Sprintf("^%s$", patterns), s)
-	if err != nil {
-		return false, fmt.Errorf("cannot compile regex %s: %w", patterns, err)
+	return fixes, nil
+}
+
+// packageNames returns a function resolving the names of the packages imported by the package of the pass, falling
+// back to DefaultPackageName for the ones the type checker did not resolve.
+func packageNames(pass *analysis.Pass) func(path string) string {
+	names := make(map[string]string)
+	for _, imp := range pass.Pkg.Imports() {
+		names[imp.Path()] = imp.Name()
 	}
 
-	return r, err
+	return func(path string) string {
+		if name, ok := names[path]; ok {
+			return name
+		}
+
+		return DefaultPackageName(path)
+	}
+}
+
+// passFile is a file of the pass along with the name of the source file on disk it is checked against.
+type passFile struct {
+	name    string
+	tokFile *token.File
+	node    *ast.File
 }
 
-func getFileNamesAndPoses(pass *analysis.Pass) ([]string, []token.Pos) {
-	var fileNames []string
-	var poses []token.Pos
+// getFiles returns the files of the pass to check, one per source file on disk. Files generated by cgo are skipped,
+// and so are the files whose positions are adjusted to a source file already returned, like the Go files cgo
+// preprocesses.
+func getFiles(pass *analysis.Pass, logger *slog.Logger) []passFile {
+	var files []passFile
+	seen := make(map[string]bool)
 	for _, f := range pass.Files {
 		fileName := pass.Fset.PositionFor(f.Pos(), true).Filename
 		ext := filepath.Ext(fileName)
 		if ext != "" && ext != ".go" {
 			// position has been adjusted to a non-go file, revert to original file
+			logger.Debug("reverting position adjusted to a non-go file", "file", fileName)
 			fileName = pass.Fset.PositionFor(f.Pos(), false).Filename
 		}
-		fileNames = append(fileNames, fileName)
-		poses = append(poses, f.Pos())
+
+		if strings.HasPrefix(filepath.Base(fileName), "_cgo_") {
+			logger.Debug("skipping file generated by cgo", "file", fileName)
+			continue
+		}
+
+		if seen[fileName] {
+			logger.Debug("skipping file already checked", "file", fileName)
+			continue
+		}
+		seen[fileName] = true
+
+		files = append(files, passFile{name: fileName, tokFile: pass.Fset.File(f.Pos()), node: f})
+	}
+	return files
+}
+
+// sameLines reports whether src has the size and the lines of file.
+func sameLines(file *token.File, src []byte) bool {
+	if len(src) != file.Size() {
+		return false
 	}
-	return fileNames, poses
+
+	for line := 2; line <= file.LineCount(); line++ {
+		offset := file.Offset(file.LineStart(line))
+		if offset == 0 || src[offset-1] != '\n' {
+			return false
+		}
+	}
+
+	newlines := bytes.Count(src, []byte("\n"))
+	if bytes.HasSuffix(src, []byte("\n")) {
+		newlines-- // a file gets no line starting at its end
+	}
+
+	return newlines == file.LineCount()-1
+}
+
+// filePos converts an offset in the file read from disk to a position in the pass, clamping it to the file size in
+// case the pass holds a preprocessed version of that file, or a registered rule returns an offset out of range.
+func filePos(file *token.File, offset int) token.Pos {
+	if offset > file.Size() {
+		offset = file.Size()
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	return file.Pos(offset)
 }
@@ -1,8 +1,19 @@
 package analyzer_test
 
 import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
 
 	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
@@ -30,9 +41,884 @@ func TestAnalyzerWithConfig(t *testing.T) {
 		t,
 		analysistest.TestData(), a,
 		"correct",
+		"less_groups",
 		"single_group",
 		"swapped_groups",
 		"no_imports",
 		"multiple_sections",
+		"unmatched",
+		"nolint",
+		"generated",
 	)
 }
+
+func TestAnalyzerDiagnosticURL(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	err := a.Flags.Lookup("groups").Value.Set("fmt:os;time;strings;regexp")
+	if err != nil {
+		t.Fail()
+	}
+
+	err = a.Flags.Lookup("docs-url").Value.Set("https://example.com/style-guide")
+	if err != nil {
+		t.Fail()
+	}
+	defer a.Flags.Lookup("docs-url").Value.Set(a.Flags.Lookup("docs-url").DefValue)
+
+	results := analysistest.Run(t, analysistest.TestData(), a, "single_group")
+
+	diagnostics := 0
+	for _, result := range results {
+		for _, diagnostic := range result.Diagnostics {
+			diagnostics++
+
+			if diagnostic.Category != "mixed-group" {
+				t.Errorf("unexpected category %q", diagnostic.Category)
+			}
+
+			if diagnostic.URL != "https://example.com/style-guide#mixed-group" {
+				t.Errorf("unexpected url %q", diagnostic.URL)
+			}
+		}
+	}
+
+	if diagnostics == 0 {
+		t.Error("expected diagnostics")
+	}
+}
+
+func TestAnalyzerMaxIssuesPerFile(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	err := a.Flags.Lookup("groups").Value.Set("fmt:os;time;strings;regexp")
+	if err != nil {
+		t.Fail()
+	}
+
+	err = a.Flags.Lookup("max-issues-per-file").Value.Set("1")
+	if err != nil {
+		t.Fail()
+	}
+	defer a.Flags.Lookup("max-issues-per-file").Value.Set(a.Flags.Lookup("max-issues-per-file").DefValue)
+
+	analysistest.Run(t, analysistest.TestData(), a, "too_many_issues")
+}
+
+func TestAnalyzerPreview(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	err := a.Flags.Lookup("groups").Value.Set("fmt:os;time;strings;regexp")
+	if err != nil {
+		t.Fail()
+	}
+
+	err = a.Flags.Lookup("preview").Value.Set("3")
+	if err != nil {
+		t.Fail()
+	}
+	defer a.Flags.Lookup("preview").Value.Set(a.Flags.Lookup("preview").DefValue)
+
+	analysistest.Run(t, analysistest.TestData(), a, "preview")
+}
+
+func TestAnalyzerDiagnosticEnd(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	err := a.Flags.Lookup("groups").Value.Set("fmt:os;time;strings;regexp")
+	if err != nil {
+		t.Fail()
+	}
+
+	results := analysistest.Run(t, analysistest.TestData(), a, "single_group")
+
+	for _, result := range results {
+		for _, diagnostic := range result.Diagnostics {
+			start := result.Pass.Fset.Position(diagnostic.Pos)
+			end := result.Pass.Fset.Position(diagnostic.End)
+
+			src, err := os.ReadFile(start.Filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			spec := string(src[start.Offset:end.Offset])
+			if !strings.Contains(diagnostic.Message, "import "+spec+" ") {
+				t.Errorf("diagnostic %q does not cover its import, covers %q", diagnostic.Message, spec)
+			}
+		}
+	}
+}
+
+func TestAnalyzerMessages(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	err := a.Flags.Lookup("groups").Value.Set("fmt:os;time;strings;regexp")
+	if err != nil {
+		t.Fail()
+	}
+
+	err = a.Flags.Lookup("messages").Value.Set(filepath.Join(analysistest.TestData(), "messages.json"))
+	if err != nil {
+		t.Fail()
+	}
+	defer a.Flags.Lookup("messages").Value.Set(a.Flags.Lookup("messages").DefValue)
+
+	analysistest.Run(t, analysistest.TestData(), a, "messages")
+}
+
+func TestAnalyzerNoiseControls(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	err := a.Flags.Lookup("groups").Value.Set("fmt:os;time;strings;regexp")
+	if err != nil {
+		t.Fail()
+	}
+
+	for name, value := range map[string]string{"max-issues": "2", "disable": "unmatched-import"} {
+		err = a.Flags.Lookup(name).Value.Set(value)
+		if err != nil {
+			t.Fail()
+		}
+		defer a.Flags.Lookup(name).Value.Set(a.Flags.Lookup(name).DefValue)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), a, "noise")
+}
+
+func TestAnalyzerRedundantAlias(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	err := a.Flags.Lookup("groups").Value.Set(".*")
+	if err != nil {
+		t.Fail()
+	}
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), a, "redundant_alias")
+}
+
+func TestAnalyzerDuplicateImport(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	err := a.Flags.Lookup("groups").Value.Set(".*")
+	if err != nil {
+		t.Fail()
+	}
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), a, "duplicate_import")
+}
+
+func TestAnalyzerWithoutTypesInfo(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	err := a.Flags.Lookup("groups").Value.Set(".*")
+	if err != nil {
+		t.Fail()
+	}
+
+	fset := token.NewFileSet()
+
+	var files []*ast.File
+	for _, name := range []string{"redundant_alias/redundant_alias.go", "duplicate_import/safe.go"} {
+		file, err := parser.ParseFile(fset, filepath.Join(analysistest.TestData(), "src", name), nil, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		files = append(files, file)
+	}
+
+	var diagnostics []analysis.Diagnostic
+	pass := &analysis.Pass{
+		Analyzer: a,
+		Fset:     fset,
+		Files:    files,
+		Pkg:      types.NewPackage("main", "main"),
+		Report: func(d analysis.Diagnostic) {
+			diagnostics = append(diagnostics, d)
+		},
+	}
+
+	_, err = a.Run(pass)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diagnostics) != 3 {
+		t.Errorf("got %d diagnostics, want 3", len(diagnostics))
+	}
+
+	for _, d := range diagnostics {
+		if len(d.SuggestedFixes) != 0 {
+			t.Errorf("diagnostic %q suggests fixes without type information", d.Message)
+		}
+	}
+}
+
+func TestAnalyzerImportComment(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	for name, value := range map[string]string{"groups": "fmt;.*", "comments": ";indirect-tool"} {
+		f := a.Flags.Lookup(name)
+
+		err := f.Value.Set(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer f.Value.Set(f.DefValue)
+	}
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), a, "import_comment")
+}
+
+func TestAnalyzerCommentedOutImport(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	err := a.Flags.Lookup("groups").Value.Set(".*")
+	if err != nil {
+		t.Fail()
+	}
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), a, "commented_import")
+}
+
+func TestAnalyzerImportPosition(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	for name, value := range map[string]string{"groups": ".*", "import-position": "true"} {
+		f := a.Flags.Lookup(name)
+
+		err := f.Value.Set(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer f.Value.Set(f.DefValue)
+	}
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), a, "import_position")
+}
+
+func TestAnalyzerSplitImportDecls(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	err := a.Flags.Lookup("groups").Value.Set(".*")
+	if err != nil {
+		t.Fail()
+	}
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), a, "split_decls")
+}
+
+func TestAnalyzerEmptyDecls(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	for name, value := range map[string]string{"groups": ".*", "report-empty-decls": "true"} {
+		f := a.Flags.Lookup(name)
+
+		err := f.Value.Set(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer f.Value.Set(f.DefValue)
+	}
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), a, "empty_decl")
+}
+
+func TestAnalyzerMergeDecls(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	f := a.Flags.Lookup("groups")
+
+	err := f.Value.Set("fmt:os;time;strings")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Value.Set(f.DefValue)
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), a, "merge_decls")
+}
+
+func TestAnalyzerDeclGroups(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	for name, value := range map[string]string{"groups": "fmt:os;time;strings;regexp", "decl-groups": "true"} {
+		f := a.Flags.Lookup(name)
+
+		err := f.Value.Set(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer f.Value.Set(f.DefValue)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), a, "decl_groups")
+}
+
+func TestAnalyzerSplitGroups(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	for name, value := range map[string]string{"groups": "fmt:os;time;strings", "report-split-groups": "true"} {
+		f := a.Flags.Lookup(name)
+
+		err := f.Value.Set(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer f.Value.Set(f.DefValue)
+	}
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), a, "split_group")
+}
+
+func TestAnalyzerRelaxedOrder(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	for name, value := range map[string]string{
+		"groups":              "fmt:os;time;strings:sort",
+		"relaxed-order":       "true",
+		"report-split-groups": "true",
+	} {
+		f := a.Flags.Lookup(name)
+
+		err := f.Value.Set(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer f.Value.Set(f.DefValue)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), a, "relaxed_order")
+}
+
+func TestAnalyzerSorted(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	for name, value := range map[string]string{"groups": "fmt:os;.*", "sorted": "true"} {
+		f := a.Flags.Lookup(name)
+
+		err := f.Value.Set(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer f.Value.Set(f.DefValue)
+	}
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), a, "sorted")
+}
+
+func TestAnalyzerNamedGroups(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	for name, value := range map[string]string{
+		"groups":          "fmt:os;time",
+		"group-names":     "core;clock",
+		"header-comments": "true",
+	} {
+		f := a.Flags.Lookup(name)
+
+		err := f.Value.Set(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer f.Value.Set(f.DefValue)
+	}
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), a, "named_groups")
+}
+
+func TestAnalyzerRequiredGroups(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	for name, value := range map[string]string{
+		"groups":          "fmt:os;time;strings",
+		"group-names":     "core",
+		"required-groups": "core,2",
+	} {
+		f := a.Flags.Lookup(name)
+
+		err := f.Value.Set(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer f.Value.Set(f.DefValue)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), a, "missing_group")
+}
+
+func TestAnalyzerCgoFiles(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	err := a.Flags.Lookup("groups").Value.Set(".*")
+	if err != nil {
+		t.Fail()
+	}
+
+	fset := token.NewFileSet()
+	name := filepath.Join(analysistest.TestData(), "src", "redundant_alias", "redundant_alias.go")
+
+	var files []*ast.File
+	for _, filename := range []string{name, name, "_cgo_gotypes.go"} {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		files = append(files, file)
+	}
+
+	var diagnostics []analysis.Diagnostic
+	pass := &analysis.Pass{
+		Analyzer: a,
+		Fset:     fset,
+		Files:    files,
+		Pkg:      types.NewPackage("main", "main"),
+		Report: func(d analysis.Diagnostic) {
+			diagnostics = append(diagnostics, d)
+		},
+	}
+
+	_, err = a.Run(pass)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diagnostics) != 2 {
+		t.Errorf("got %d diagnostics, want 2 as the file is checked once and the cgo file not at all", len(diagnostics))
+	}
+}
+
+// flakyFS fails the first read of each file with a transient error.
+type flakyFS struct {
+	files  fstest.MapFS
+	failed map[string]bool
+}
+
+func (f flakyFS) Open(name string) (fs.File, error) {
+	if !f.failed[name] {
+		f.failed[name] = true
+		return nil, errors.New("stale file handle")
+	}
+
+	return f.files.Open(name)
+}
+
+func TestAnalyzerFS(t *testing.T) {
+	src := []byte("package main\n\nimport (\n\tfmt \"fmt\"\n)\n\nvar _ = fmt.Println\n")
+
+	for _, retries := range []string{"0", "1"} {
+		a := analyzer.NewAnalyzerFS(flakyFS{
+			files:  fstest.MapFS{"virtual/main.go": {Data: src}},
+			failed: make(map[string]bool),
+		})
+
+		for name, value := range map[string]string{"groups": ".*", "read-retries": retries} {
+			f := a.Flags.Lookup(name)
+
+			err := f.Value.Set(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer f.Value.Set(f.DefValue)
+		}
+
+		fset := token.NewFileSet()
+
+		file, err := parser.ParseFile(fset, "/virtual/main.go", src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var diagnostics []analysis.Diagnostic
+		pass := &analysis.Pass{
+			Analyzer: a,
+			Fset:     fset,
+			Files:    []*ast.File{file},
+			Pkg:      types.NewPackage("main", "main"),
+			Report: func(d analysis.Diagnostic) {
+				diagnostics = append(diagnostics, d)
+			},
+		}
+
+		_, err = a.Run(pass)
+		if retries == "0" && !errors.Is(err, analyzer.ErrIO) {
+			t.Errorf("expected the transient error without retries, got %v", err)
+		}
+
+		if retries == "1" && (err != nil || len(diagnostics) != 1) {
+			t.Errorf("expected a single diagnostic after retrying, got %v, %v", diagnostics, err)
+		}
+	}
+}
+
+func TestAnalyzerOverlay(t *testing.T) {
+	// the disk holds an outdated version of the file checked by the pass
+	disk := []byte("package main\n\nimport \"fmt\"\n\nvar _ = fmt.Println\n")
+	src := []byte("package main\n\n// the imports\nimport (\n\t\"os\"\n\t\"fmt\"\n)\n\nvar _, _ = fmt.Println, os.Exit\n")
+
+	a := analyzer.NewAnalyzerFS(fstest.MapFS{"virtual/main.go": {Data: disk}})
+
+	f := a.Flags.Lookup("groups")
+	defer f.Value.Set(f.DefValue)
+
+	err := f.Value.Set("fmt;os")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "/virtual/main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var diagnostics []analysis.Diagnostic
+	pass := &analysis.Pass{
+		Analyzer: a,
+		Fset:     fset,
+		Files:    []*ast.File{file},
+		Pkg:      types.NewPackage("main", "main"),
+		Report: func(d analysis.Diagnostic) {
+			diagnostics = append(diagnostics, d)
+		},
+	}
+
+	_, err = a.Run(pass)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diagnostics) != 1 || fset.Position(diagnostics[0].Pos).Line != 6 {
+		t.Fatalf("expected a single diagnostic on line 6, got %v", diagnostics)
+	}
+
+	if len(diagnostics[0].SuggestedFixes) != 0 {
+		t.Errorf("expected no fix built from the content on disk, got %v", diagnostics[0].SuggestedFixes)
+	}
+}
+
+func TestAnalyzerHermetic(t *testing.T) {
+	// the file of the pass does not exist on disk, like in the sandboxes of nogo
+	src := []byte("package main\n\nimport (\n\t\"os\"\n\t\"fmt\"\n)\n\nvar _, _ = fmt.Println, os.Exit\n")
+
+	a := analyzer.NewAnalyzerHermetic()
+
+	f := a.Flags.Lookup("groups")
+	defer f.Value.Set(f.DefValue)
+
+	err := f.Value.Set("fmt;os")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "/sandbox/missing/main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var diagnostics []analysis.Diagnostic
+	pass := &analysis.Pass{
+		Analyzer: a,
+		Fset:     fset,
+		Files:    []*ast.File{file},
+		Pkg:      types.NewPackage("main", "main"),
+		Report: func(d analysis.Diagnostic) {
+			diagnostics = append(diagnostics, d)
+		},
+	}
+
+	_, err = a.Run(pass)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diagnostics) != 1 || fset.Position(diagnostics[0].Pos).Line != 5 {
+		t.Fatalf("expected a single diagnostic on line 5, got %v", diagnostics)
+	}
+
+	if len(diagnostics[0].SuggestedFixes) != 0 {
+		t.Errorf("expected no fix without the text of the file, got %v", diagnostics[0].SuggestedFixes)
+	}
+}
+
+func TestAnalyzerPassReadFile(t *testing.T) {
+	// the driver supplies the unsaved content of a file missing on disk, like gopls does with its overlays
+	src := []byte("package main\n\nimport (\n\t\"os\"\n\t\"fmt\"\n)\n\nvar _, _ = fmt.Println, os.Exit\n")
+
+	for name, a := range map[string]*analysis.Analyzer{
+		"NewAnalyzer":         analyzer.NewAnalyzer(),
+		"NewAnalyzerHermetic": analyzer.NewAnalyzerHermetic(),
+	} {
+		err := a.Flags.Lookup("groups").Value.Set("fmt;os")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fset := token.NewFileSet()
+
+		file, err := parser.ParseFile(fset, "/overlay/missing/main.go", src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var diagnostics []analysis.Diagnostic
+		pass := &analysis.Pass{
+			Analyzer: a,
+			Fset:     fset,
+			Files:    []*ast.File{file},
+			Pkg:      types.NewPackage("main", "main"),
+			Report: func(d analysis.Diagnostic) {
+				diagnostics = append(diagnostics, d)
+			},
+			ReadFile: func(filename string) ([]byte, error) {
+				if filename != "/overlay/missing/main.go" {
+					return nil, os.ErrNotExist
+				}
+
+				return src, nil
+			},
+		}
+
+		_, err = a.Run(pass)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if len(diagnostics) != 1 || len(diagnostics[0].SuggestedFixes) != 1 {
+			t.Errorf("%s: expected a single diagnostic with a fix built from the text of the pass, got %v", name,
+				diagnostics)
+		}
+	}
+}
+
+func TestAnalyzerFileNotFound(t *testing.T) {
+	// sandboxed drivers may set a ReadFile finding none of the files of the pass
+	src := []byte("package main\n\nimport (\n\t\"os\"\n\n\t\"fmt\"\n)\n\nvar _, _ = fmt.Println, os.Exit\n")
+
+	a := analyzer.NewAnalyzer()
+
+	err := a.Flags.Lookup("groups").Value.Set("fmt;os")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "/sandbox/main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var diagnostics []analysis.Diagnostic
+	pass := &analysis.Pass{
+		Analyzer: a,
+		Fset:     fset,
+		Files:    []*ast.File{file},
+		Pkg:      types.NewPackage("main", "main"),
+		Report: func(d analysis.Diagnostic) {
+			diagnostics = append(diagnostics, d)
+		},
+		ReadFile: func(filename string) ([]byte, error) {
+			return nil, &fs.PathError{Op: "open", Path: filename, Err: fs.ErrNotExist}
+		},
+	}
+
+	_, err = a.Run(pass)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diagnostics) != 1 || diagnostics[0].Category != "group-order" {
+		t.Fatalf("expected the group order to be checked on the syntax tree, got %v", diagnostics)
+	}
+
+	if len(diagnostics[0].SuggestedFixes) != 0 {
+		t.Errorf("expected no fix without the text of the file, got %v", diagnostics[0].SuggestedFixes)
+	}
+}
+
+// deniedFS denies the reads of the files missing from files.
+type deniedFS struct {
+	files fstest.MapFS
+}
+
+func (f deniedFS) Open(name string) (fs.File, error) {
+	if f.files[name] == nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+
+	return f.files.Open(name)
+}
+
+func TestAnalyzerJoinedErrors(t *testing.T) {
+	src := []byte("package main\n\nimport (\n\tfmt \"fmt\"\n)\n\nvar _ = fmt.Println\n")
+
+	a := analyzer.NewAnalyzerFS(deniedFS{files: fstest.MapFS{"virtual/present.go": {Data: src}}})
+
+	err := a.Flags.Lookup("groups").Value.Set(".*")
+	if err != nil {
+		t.Fail()
+	}
+
+	fset := token.NewFileSet()
+
+	var files []*ast.File
+	for _, name := range []string{"/virtual/missing.go", "/virtual/present.go", "/virtual/gone.go"} {
+		file, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		files = append(files, file)
+	}
+
+	var diagnostics []analysis.Diagnostic
+	pass := &analysis.Pass{
+		Analyzer: a,
+		Fset:     fset,
+		Files:    files,
+		Pkg:      types.NewPackage("main", "main"),
+		Report: func(d analysis.Diagnostic) {
+			diagnostics = append(diagnostics, d)
+		},
+	}
+
+	_, err = a.Run(pass)
+	if !errors.Is(err, analyzer.ErrIO) || !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("expected IO errors for the denied files, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "missing.go") || !strings.Contains(err.Error(), "gone.go") {
+		t.Errorf("expected the errors of both denied files, got %v", err)
+	}
+
+	if len(diagnostics) != 1 {
+		t.Errorf("expected the present file to be checked, got %v", diagnostics)
+	}
+}
+
+func TestAnalyzerRegroup(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	err := a.Flags.Lookup("groups").Value.Set("fmt:os;time;strings;regexp")
+	if err != nil {
+		t.Fail()
+	}
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), a, "regroup")
+}
+
+func TestAnalyzerResult(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	err := a.Flags.Lookup("groups").Value.Set("fmt:os;time;strings;regexp")
+	if err != nil {
+		t.Fail()
+	}
+
+	results := analysistest.Run(t, analysistest.TestData(), a, "swapped_groups")
+
+	imports := results[0].Result.(*analyzer.Imports)
+	if len(imports.Files) != 1 || filepath.Base(imports.Files[0].Name) != "swapped_groups.go" {
+		t.Fatalf("expected the imports of swapped_groups.go, got %+v", imports.Files)
+	}
+
+	var got []string
+	for _, group := range imports.Files[0].Groups {
+		var paths []string
+		for _, imp := range group.Imports {
+			paths = append(paths, imp.Path)
+		}
+
+		got = append(got, group.Name+"="+strings.Join(paths, ","))
+	}
+
+	if want := "fmt:os=fmt,os time=time strings=strings regexp=regexp"; strings.Join(got, " ") != want {
+		t.Errorf("expected the groups %s, got %s", want, strings.Join(got, " "))
+	}
+}
+
+func TestAnalyzerConfigFile(t *testing.T) {
+	// the groups of the configuration file take precedence over the flags left unset
+	analysistest.Run(t, analysistest.TestData(), analyzer.NewAnalyzer(), "yaml_config")
+}
+
+func TestAnalyzerVerbose(t *testing.T) {
+	for flag, want := range map[string][]string{
+		"v": {
+			`level=INFO msg="loading configuration file" package=yaml_config file=`,
+			`level=INFO msg="resolved configuration" package=yaml_config groups=fmt;time preset=""`,
+			`level=INFO msg="checked package" package=yaml_config files=1 issues=1 errors=0`,
+		},
+		"vv": {
+			`level=DEBUG msg="checking file" package=yaml_config file=`,
+			`level=DEBUG msg="checked file" package=yaml_config file=`,
+		},
+	} {
+		a := analyzer.NewAnalyzer()
+		if err := a.Flags.Lookup(flag).Value.Set("true"); err != nil {
+			t.Fatal(err)
+		}
+
+		stderr, err := os.CreateTemp(t.TempDir(), "stderr")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		os.Stderr, stderr = stderr, os.Stderr
+		analysistest.Run(t, analysistest.TestData(), a, "yaml_config")
+		os.Stderr, stderr = stderr, os.Stderr
+
+		logs, err := os.ReadFile(stderr.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, line := range want {
+			if !strings.Contains(string(logs), line) {
+				t.Errorf("-%s: expected the logs to hold %s, got\n%s", flag, line, logs)
+			}
+		}
+
+		if flag == "v" && strings.Contains(string(logs), "level=DEBUG") {
+			t.Errorf("-v: expected no debug logs, got\n%s", logs)
+		}
+	}
+}
+
+func TestAnalyzerLocalModule(t *testing.T) {
+	a := analyzer.NewAnalyzer()
+
+	f := a.Flags.Lookup("groups")
+	defer func() {
+		_ = f.Value.Set(f.DefValue)
+	}()
+
+	// the module path is read from testdata/src/localmodule/go.mod
+	err := f.Value.Set("std;localmodule")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), a, "localmodule")
+}
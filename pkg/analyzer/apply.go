@@ -0,0 +1,108 @@
+package analyzer
+
+import (
+	"bytes"
+	"sort"
+)
+
+// maxFixRounds caps the rounds of fixes applied to a source, fixes overlapping the ones of a round being left for the
+// next.
+const maxFixRounds = 10
+
+// FixSource returns src with the fixes of its issues against cfg applied, as Checker.FixBytes does. It returns src
+// itself if src has no issues to fix, so it leaves conforming sources as they are.
+func FixSource(src []byte, cfg Config) ([]byte, error) {
+	c, err := NewChecker(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	fixed, _, err := c.FixBytes("", src)
+
+	return fixed, err
+}
+
+// FixBytes applies the fixes of the issues of src, the content of the file name, in rounds until none is left to
+// apply, and returns the fixed source, src itself if no fix applies, along with the issues left. The fixes of
+// redundant aliases are left out, as they rely on package names guessed from the import paths without type
+// information, which could break the source if wrong.
+func (c *Checker) FixBytes(name string, src []byte) ([]byte, []Issue, error) {
+	issues, err := c.CheckBytes(name, src)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fixed := src
+	for round := 0; round < maxFixRounds; round++ {
+		next, applied := ApplyFixes(fixed, withoutAliasFixes(issues))
+		if applied == 0 {
+			break
+		}
+
+		fixed = next
+		issues, err = c.CheckBytes(name, fixed)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return fixed, issues, nil
+}
+
+// withoutAliasFixes returns issues without the ones about redundant aliases.
+func withoutAliasFixes(issues []Issue) []Issue {
+	var filtered []Issue
+	for _, iss := range issues {
+		if iss.Code != codeRedundantAlias {
+			filtered = append(filtered, iss)
+		}
+	}
+
+	return filtered
+}
+
+// ApplyFixes applies the first fix of each issue to src, the source the issues were found in, and returns the result
+// along with the number of fixes applied. A fix whose edits overlap the ones of a fix applied before is skipped, as
+// checking the result again offers it anew if it is still needed.
+func ApplyFixes(src []byte, issues []Issue) ([]byte, int) {
+	var edits []Edit
+	applied := 0
+	for _, iss := range issues {
+		if len(iss.Fixes) == 0 || overlaps(edits, iss.Fixes[0].Edits) {
+			continue
+		}
+
+		edits = append(edits, iss.Fixes[0].Edits...)
+		applied++
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].Pos.Offset < edits[j].Pos.Offset
+	})
+
+	var buf bytes.Buffer
+	last := 0
+	for _, e := range edits {
+		buf.Write(src[last:e.Pos.Offset])
+		buf.Write(e.NewText)
+		last = e.End.Offset
+	}
+
+	buf.Write(src[last:])
+
+	return buf.Bytes(), applied
+}
+
+// overlaps reports whether any of edits overlaps any of accepted. Insertions at the same offset overlap too, since
+// their order would be arbitrary.
+func overlaps(accepted, edits []Edit) bool {
+	for _, a := range accepted {
+		for _, e := range edits {
+			if e.Pos.Offset < a.End.Offset && a.Pos.Offset < e.End.Offset || e.Pos.Offset == a.Pos.Offset {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,50 @@
+package analyzer
+
+import "go/ast"
+
+// isCgoImport reports whether spec imports "C", the pseudo-package of cgo. Its doc comment is the cgo preamble, which
+// has to stay right above it.
+func isCgoImport(spec *ast.ImportSpec) bool {
+	return importPath(spec) == "C"
+}
+
+// isCgoDecl reports whether decl only imports "C". The preamble is then the doc comment of decl.
+func isCgoDecl(decl *ast.GenDecl) bool {
+	return len(decl.Specs) == 1 && isCgoImport(decl.Specs[0].(*ast.ImportSpec))
+}
+
+// importsCgo reports whether one of decls imports "C".
+func importsCgo(decls ...*ast.GenDecl) bool {
+	for _, decl := range decls {
+		for _, s := range decl.Specs {
+			if isCgoImport(s.(*ast.ImportSpec)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// withoutCgoDecls returns decls without the declarations only importing "C", which are left as they are.
+func withoutCgoDecls(decls []*ast.GenDecl) []*ast.GenDecl {
+	var rest []*ast.GenDecl
+	for _, decl := range decls {
+		if !isCgoDecl(decl) {
+			rest = append(rest, decl)
+		}
+	}
+
+	return rest
+}
+
+// previousDecl returns the declaration of fileNode right before decl, nil if decl is the first one.
+func previousDecl(fileNode *ast.File, decl ast.Decl) ast.Decl {
+	for i, d := range fileNode.Decls {
+		if d == decl && i > 0 {
+			return fileNode.Decls[i-1]
+		}
+	}
+
+	return nil
+}
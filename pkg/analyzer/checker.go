@@ -0,0 +1,943 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	codeMultipleImportDecls = "multiple-import-decls"
+	codeGroupOrder          = "group-order"
+	codeMixedGroup          = "mixed-group"
+	codeSplitGroup          = "split-group"
+	codeMissingGroup        = "missing-group"
+	codeUnsortedImport      = "unsorted-import"
+	codeUnmatchedImport     = "unmatched-import"
+	codeIssueLimit          = "issue-limit"
+	codeGlobalIssueLimit    = "global-issue-limit"
+	codeRedundantAlias      = "redundant-alias"
+	codeDuplicateImport     = "duplicate-import"
+	codeImportComment       = "import-comment"
+	codeCommentedOutImport  = "commented-out-import"
+	codeImportPosition      = "import-position"
+	codeSplitImportDecls    = "split-import-decls"
+	codeEmptyImportDecl     = "empty-import-decl"
+)
+
+// Config configures the checks.
+type Config struct {
+	// Groups is the boolean expression of import path regex patterns, one per group, separated by semicolons.
+	Groups string
+	// Preset is the name of a built-in layout of groups, std-thirdparty, std-thirdparty-local or
+	// std-thirdparty-org-local, setting Groups and GroupNames in place of their own values. The org group holds the
+	// packages of the organization of LocalModule, the module path without its last element.
+	Preset string
+	// DocsURL is the base URL of the rule documentation.
+	DocsURL string
+	// MaxIssuesPerFile caps the issues reported per file, 0 means no limit.
+	MaxIssuesPerFile int
+	// CollapseIdentical reports issues with identical messages only once per file.
+	CollapseIdentical bool
+	// Disable is a comma separated list of rule codes not to report.
+	Disable string
+	// Severity is the severity of the issues, SeverityError, the default, or SeverityWarning.
+	Severity string
+	// Severities is a comma separated list of code=severity pairs overriding Severity for the issues of the rules of
+	// the codes, like unsorted-import=warning to roll a rule out gradually.
+	Severities string
+	// Preview is the number of lines of the expected import block included in the first issue of a file.
+	Preview int
+	// Explain includes in the first issue of a file the group each block of its imports was matched to.
+	Explain bool
+	// Messages is a JSON file overriding the default message templates.
+	Messages string
+	// Sort is the SortOrder of the imports within each group of rendered import blocks, SortPath by default so the
+	// fixes leave the files as gofmt does.
+	Sort string
+	// Sorted reports the imports out of the Sort order within their block, SortPath if Sort is SortNone.
+	Sorted bool
+	// SinkBlankDot moves blank and dot imports to the end of their group in rendered import blocks.
+	SinkBlankDot bool
+	// AlignAliases, NormalizeQuotes and RemoveRedundantAliases set the Style options of the same name for rendered
+	// import blocks.
+	AlignAliases           bool
+	NormalizeQuotes        bool
+	RemoveRedundantAliases bool
+	// Comments is a list of regex patterns, one per group, separated by semicolons, that the trailing comments of the
+	// imports of the group must match. An empty pattern leaves the comments of its group unchecked.
+	Comments string
+	// ImportPosition reports the import declarations not following the package clause right away.
+	ImportPosition bool
+	// Policies is a comma separated list of the names of policy packs registered with RegisterPolicy. They are applied
+	// in order over the rest of the configuration when the Checker is created.
+	Policies string
+	// Exclude is a list of patterns, separated by semicolons, of the paths of the files not to check. They are regexes
+	// or, with PatternSyntaxGlob, globs matching a path either as a whole or from any of its elements on.
+	Exclude string
+	// IncludeGenerated checks the files with a "// Code generated ... DO NOT EDIT." comment, which are skipped
+	// otherwise.
+	IncludeGenerated bool
+	// ReportEmptyDecls reports the import declarations without imports, which are otherwise checked like any other.
+	ReportEmptyDecls bool
+	// ReportSplitGroups reports the blocks of imports belonging to the same group as the block before them, or as any
+	// block before them in relaxed order.
+	ReportSplitGroups bool
+	// DeclGroups makes each import declaration a block of its own, blank lines included, instead of reporting all
+	// declarations after the first. No fixes are suggested for the groups of the blocks then.
+	DeclGroups bool
+	// RelaxedOrder lets the blocks of imports come in any order of their groups, as long as each of them belongs to a
+	// single group.
+	RelaxedOrder bool
+	// GroupNames is a list of names, one per group, separated by semicolons, used instead of the patterns of the groups
+	// in messages and header comments. An empty name leaves the pattern in use, and \; stands for a semicolon.
+	GroupNames string
+	// RequiredGroups is a comma separated list of the numbers, starting at 1, or the names of the groups every import
+	// declaration has to import packages of.
+	RequiredGroups string
+	// HeaderComments heads each block of the imports rendered by the fixes with a comment naming its group.
+	HeaderComments bool
+	// PatternSyntax is the syntax of the patterns of Groups, PatternSyntaxRegex, the default, or PatternSyntaxGlob.
+	PatternSyntax string
+	// LocalModule is the module path matched by the localmodule keyword of Groups. The analyzer reads it from the
+	// nearest go.mod of each package when it is empty.
+	LocalModule string
+	// GoVersion is the Go version of the checked files, like go1.22, the std keyword of Groups matching the packages
+	// of its standard library only. The analyzer reads it from the go directive of the nearest go.mod of each package
+	// when it is empty, and std matches the packages of every known version if it stays empty.
+	GoVersion string
+}
+
+// DefaultConfig returns the configuration used when nothing else is specified.
+func DefaultConfig() Config {
+	return Config{
+		Groups:           ".*",
+		DocsURL:          "https://github.com/kmirzavaziri/goimportgroups",
+		MaxIssuesPerFile: 10,
+		Sort:             string(SortPath),
+	}
+}
+
+// NamedSource is the content of a Go source file along with its name.
+type NamedSource struct {
+	Name string
+	Src  []byte
+}
+
+// Result holds the issues found in a NamedSource, or the error that prevented checking it.
+type Result struct {
+	Name   string
+	Issues []Issue
+	Err    error
+}
+
+// Issue is a single violation found in a file.
+type Issue struct {
+	Pos     token.Position
+	End     token.Position
+	Code    string
+	Message string
+	Fixes   []Fix
+	// Path is the import path the issue is about, if any.
+	Path string
+	// Expected is the name of the group the import of Path belongs to, or its pattern if it has none, if the issue is
+	// about its group.
+	Expected string
+	// Severity is the severity of the rule of Code, SeverityError or SeverityWarning.
+	Severity string
+}
+
+// Fix is a possible fix of an Issue.
+type Fix struct {
+	Message string
+	Edits   []Edit
+}
+
+// Edit replaces the bytes of a file between the offsets of Pos and End with NewText.
+type Edit struct {
+	Pos     token.Position
+	End     token.Position
+	NewText []byte
+}
+
+// Checker checks sources against a Config. It compiles the configuration once and reuses it, along with its
+// buffers, across all the files it checks, so it is meant to be long-lived. A Checker is not safe for concurrent use.
+type Checker struct {
+	cfg             Config
+	patterns        []string
+	names           []string
+	required        []int
+	commentPatterns []string
+	matcher         *matcher
+	commentMatcher  *matcher
+	excludes        []expr
+	defaultSeverity string
+	severities      map[string]string
+	messages        catalog
+	style           Style
+	rules           []Rule
+	logger          *slog.Logger
+}
+
+type issue struct {
+	pos   int
+	end   int
+	code  string
+	args  messageArgs
+	fixes []fix
+}
+
+type fix struct {
+	message string // key of the message in the catalog
+	text    string // message of the fixes of registered rules, which have no key
+	edits   []edit
+}
+
+type edit struct {
+	pos     int
+	end     int
+	newText string
+}
+
+type importSpec struct {
+	path  string
+	group int
+	node  *ast.ImportSpec
+}
+
+// NewChecker returns a Checker for cfg.
+func NewChecker(cfg Config) (*Checker, error) {
+	return newChecker(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func newChecker(cfg Config, logger *slog.Logger) (*Checker, error) {
+	cfg, err := applyPolicies(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err = applyPreset(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := registeredRules()
+
+	messages, err := loadCatalog(cfg.Messages, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := ParseSortOrder(cfg.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Sorted && order == SortNone {
+		order = SortPath
+	}
+
+	glob, err := isGlobSyntax(cfg.PatternSyntax)
+	if err != nil {
+		return nil, err
+	}
+
+	// parse the group and comment patterns right away for syntax errors to surface before any file is checked
+	patterns, m := splitPatterns(cfg.Groups), newMatcher(cfg.LocalModule, cfg.GoVersion, glob)
+	for i, pattern := range patterns {
+		if _, err := m.expr(pattern); err != nil {
+			return nil, fmt.Errorf("%w (group %d)", err, i+1)
+		}
+	}
+
+	var commentPatterns []string
+	commentMatcher := newMatcher(cfg.LocalModule, cfg.GoVersion, false)
+	if cfg.Comments != "" {
+		commentPatterns = splitPatterns(cfg.Comments)
+		if len(commentPatterns) > len(patterns) {
+			return nil, fmt.Errorf("%w: %d comment patterns for %d groups", ErrConfigInvalid, len(commentPatterns),
+				len(patterns))
+		}
+
+		for i, pattern := range commentPatterns {
+			if _, err := commentMatcher.expr(pattern); pattern != "" && err != nil {
+				return nil, fmt.Errorf("%w (comment of group %d)", err, i+1)
+			}
+		}
+	}
+
+	var excludes []expr
+	if cfg.Exclude != "" {
+		em := newMatcher("", "", glob)
+		for _, pattern := range splitPatterns(cfg.Exclude) {
+			e, err := em.regexExpr(unescapePattern(pattern))
+			if err != nil {
+				return nil, err
+			}
+
+			excludes = append(excludes, e)
+		}
+	}
+
+	names := make([]string, len(patterns))
+	copy(names, patterns)
+	if cfg.GroupNames != "" {
+		given := splitPatterns(cfg.GroupNames)
+		if len(given) > len(patterns) {
+			return nil, fmt.Errorf("%w: %d group names for %d groups", ErrConfigInvalid, len(given), len(patterns))
+		}
+
+		for i, name := range given {
+			if name != "" {
+				names[i] = strings.ReplaceAll(name, `\;`, ";")
+			}
+		}
+	}
+
+	required, err := parseRequiredGroups(cfg.RequiredGroups, names)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultSeverity, err := parseSeverity(cfg.Severity)
+	if err != nil {
+		return nil, err
+	}
+
+	severities, err := parseSeverities(cfg.Severities)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Checker{
+		cfg:             cfg,
+		patterns:        patterns,
+		names:           names,
+		required:        required,
+		commentPatterns: commentPatterns,
+		matcher:         m,
+		commentMatcher:  commentMatcher,
+		excludes:        excludes,
+		defaultSeverity: defaultSeverity,
+		severities:      severities,
+		messages:        messages,
+		style: Style{
+			Sort:                   order,
+			SinkBlankDot:           cfg.SinkBlankDot,
+			HeaderComments:         cfg.HeaderComments,
+			AlignAliases:           cfg.AlignAliases,
+			NormalizeQuotes:        cfg.NormalizeQuotes,
+			RemoveRedundantAliases: cfg.RemoveRedundantAliases,
+		},
+		rules:  rules,
+		logger: logger,
+	}, nil
+}
+
+// CheckFiles checks files one after the other, sharing a single token.FileSet between them, and returns one Result
+// per file in the same order.
+func (c *Checker) CheckFiles(files []NamedSource) []Result {
+	fset := token.NewFileSet()
+
+	results := make([]Result, len(files))
+	for i, file := range files {
+		results[i].Name = file.Name
+		results[i].Issues, results[i].Err = c.checkSource(fset, file.Name, file.Src)
+	}
+
+	return results
+}
+
+// CheckBytes checks src as the content of the file name. It never panics, whatever src holds: a failure of the
+// checks, including the ones of registered rules, is returned as an error, so it is safe to use on untrusted input.
+func (c *Checker) CheckBytes(name string, src []byte) ([]Issue, error) {
+	return c.checkSource(token.NewFileSet(), name, src)
+}
+
+// Check checks the single source src against cfg, for the callers checking a source once. The positions of the issues
+// have no file name. Callers checking several sources reuse a Checker instead, which compiles cfg once.
+func Check(src []byte, cfg Config) ([]Issue, error) {
+	c, err := NewChecker(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.CheckBytes("", src)
+}
+
+func (c *Checker) checkSource(fset *token.FileSet, name string, src []byte) (issues []Issue, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			issues, err = nil, fmt.Errorf("cannot check %s: %v", name, r)
+		}
+	}()
+
+	base := fset.Base()
+
+	found, err := c.check(fset, name, src, DefaultPackageName)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.exportIssues(fset.File(token.Pos(base)), found)
+}
+
+// CheckDir checks the Go files in dir of fsys and its subdirectories, except for the ones the go command ignores:
+// testdata and the directories whose name starts with a dot or an underscore. It returns one Result per file, named by
+// its path in fsys, in lexical order.
+func (c *Checker) CheckDir(fsys fs.FS, dir string) ([]Result, error) {
+	var files []NamedSource
+	err := fs.WalkDir(fsys, dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrIO, err)
+		}
+
+		base := d.Name()
+		if d.IsDir() {
+			if name != dir && (base == "testdata" || strings.HasPrefix(base, ".") || strings.HasPrefix(base, "_")) {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if path.Ext(base) != ".go" {
+			return nil
+		}
+
+		src, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrIO, err)
+		}
+
+		files = append(files, NamedSource{Name: name, Src: src})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.CheckFiles(files), nil
+}
+
+func (c *Checker) exportIssues(tokFile *token.File, issues []issue) ([]Issue, error) {
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	exported := make([]Issue, len(issues))
+	for i, iss := range issues {
+		msg, err := c.messages.message(iss)
+		if err != nil {
+			return nil, err
+		}
+
+		exported[i] = Issue{
+			Pos:      tokFile.Position(filePos(tokFile, iss.pos)),
+			End:      tokFile.Position(filePos(tokFile, iss.end)),
+			Code:     iss.code,
+			Severity: c.severity(iss.code),
+			Message:  msg,
+			Path:     iss.args.Path,
+			Expected: iss.args.Expected,
+		}
+
+		for _, f := range iss.fixes {
+			fixMsg, err := c.messages.fixMessage(f, iss.args)
+			if err != nil {
+				return nil, err
+			}
+
+			exportedFix := Fix{Message: fixMsg}
+			for _, e := range f.edits {
+				exportedFix.Edits = append(exportedFix.Edits, Edit{
+					Pos:     tokFile.Position(filePos(tokFile, e.pos)),
+					End:     tokFile.Position(filePos(tokFile, e.end)),
+					NewText: []byte(e.newText),
+				})
+			}
+
+			exported[i].Fixes = append(exported[i].Fixes, exportedFix)
+		}
+	}
+
+	return exported, nil
+}
+
+func (c *Checker) ruleURL(code string) string {
+	return fmt.Sprintf("%s#%s", strings.TrimSuffix(c.cfg.DocsURL, "#"), code)
+}
+
+// check returns the issues found in src, already filtered and capped as configured. packageName returns the name of
+// the package imported by a path.
+func (c *Checker) check(
+	fset *token.FileSet, filename string, src []byte, packageName func(path string) string,
+) ([]issue, error) {
+	fileNode, err := parseImports(fset, filename, src)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.checkNode(fset, fileNode, src, packageName)
+}
+
+// checkNode is like check for a file already parsed into fileNode, with at least its imports and comments. src is the
+// content of the file, or nil if it is unknown, in which case the checks and fixes needing it are skipped.
+func (c *Checker) checkNode(
+	fset *token.FileSet, fileNode *ast.File, src []byte, packageName func(path string) string,
+) ([]issue, error) {
+	issues, err := c.findIssues(fset, fileNode, src, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.limitIssues(firstRegroupFix(dedupeIssues(c.filterIssues(issues)))), nil
+}
+
+func parseImports(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+	fileNode, err := parser.ParseFile(fset, filename, src, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParse, err)
+	}
+
+	return fileNode, nil
+}
+
+func (c *Checker) findIssues(
+	fset *token.FileSet, fileNode *ast.File, src []byte, packageName func(path string) string,
+) ([]issue, error) {
+	tokFile := fset.File(fileNode.Pos())
+	filename := tokFile.Name()
+
+	all := getImportDecls(fileNode)
+	if len(all) == 0 {
+		c.logger.Debug("skipping file without imports", "file", filename)
+		return nil, nil
+	}
+
+	decls := withoutCgoDecls(all)
+	if len(decls) == 0 {
+		c.logger.Debug("skipping file only importing C", "file", filename)
+		return nil, nil
+	}
+
+	if c.isExcluded(filename) {
+		c.logger.Debug("skipping excluded file", "file", filename)
+		return nil, nil
+	}
+
+	if isNolintFile(tokFile, fileNode) {
+		c.logger.Debug("skipping file with a nolint directive", "file", filename)
+		return nil, nil
+	}
+
+	if !c.cfg.IncludeGenerated && ast.IsGenerated(fileNode) {
+		c.logger.Debug("skipping generated file", "file", filename)
+		return nil, nil
+	}
+
+	var issues []issue
+	if c.cfg.ReportEmptyDecls {
+		issues, decls = findEmptyDecls(tokFile, decls)
+		if len(decls) == 0 {
+			return dropNolint(tokFile, fileNode, issues), nil
+		}
+	}
+
+	groupingIssues, err := c.findGroupingIssues(filename, tokFile, src, fileNode, decls)
+	if err != nil {
+		return nil, err
+	}
+
+	issues = append(issues, groupingIssues...)
+	issues = append(issues, findRedundantAliases(tokFile, decls, packageName)...)
+	issues = append(issues, findDuplicateImports(tokFile, decls, packageName)...)
+
+	commentIssues, err := c.findCommentIssues(tokFile, decls)
+	if err != nil {
+		return nil, err
+	}
+
+	issues = append(issues, commentIssues...)
+	if src != nil {
+		issues = append(issues, findCommentedOutImports(tokFile, src, decls)...)
+	}
+
+	if c.cfg.ImportPosition && src != nil {
+		issues = append(issues, findPositionIssue(tokFile, src, fileNode, all[0])...)
+	}
+
+	ruleIssues, err := c.findRuleIssues(fset, tokFile, fileNode, decls)
+	if err != nil {
+		return nil, err
+	}
+
+	issues = append(issues, ruleIssues...)
+	issues = dropNolint(tokFile, fileNode, issues)
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].pos < issues[j].pos
+	})
+
+	return issues, nil
+}
+
+func (c *Checker) findGroupingIssues(
+	filename string, tokFile *token.File, src []byte, fileNode *ast.File, decls []*ast.GenDecl,
+) ([]issue, error) {
+	groupNames := c.names
+
+	if len(decls) > 1 && !c.cfg.DeclGroups {
+		c.logger.Debug("skipping group checks of file with multiple import declarations", "file", filename)
+
+		var issues []issue
+		split := false
+		for i, decl := range decls[1:] {
+			if src != nil {
+				if iss, ok := findSplitDecl(tokFile, src, fileNode, decls, i+1); ok {
+					issues = append(issues, iss)
+					split = true
+					continue
+				}
+			}
+
+			issues = append(issues, newIssue(tokFile, decl, codeMultipleImportDecls, messageArgs{
+				Line: tokFile.Line(decls[0].Pos()),
+			}))
+		}
+
+		if src == nil || split {
+			return issues, nil
+		}
+
+		// the fixes of split declarations keep the comments in between, merging all of them would not
+		var blocks [][]importSpec
+		for _, decl := range decls {
+			blocks = append(blocks, getImportBlocks(tokFile, decl)...)
+		}
+
+		err := c.groupBlocks(blocks)
+		if err != nil {
+			return nil, err
+		}
+
+		if f, ok := mergeDeclsFix(tokFile, fileNode, decls, blocks, groupNames, c.cfg.RelaxedOrder, c.style); ok {
+			issues[0].fixes = []fix{f}
+		}
+
+		return issues, nil
+	}
+
+	var blocks [][]importSpec
+	if c.cfg.DeclGroups {
+		for _, decl := range decls {
+			blocks = append(blocks, getDeclBlock(decl))
+		}
+	} else {
+		blocks = getImportBlocks(tokFile, decls[0])
+	}
+
+	err := c.groupBlocks(blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []issue
+
+	currPatternI := 0
+	anchored := false          // whether a block before the current one belongs to a group
+	seen := make(map[int]bool) // the groups of the blocks so far, used in relaxed order
+	layout := newLayout(len(blocks))
+	for bi, block := range blocks {
+		anchor := -1
+		anchorPatternI := -1
+
+		for i, spec := range block {
+			if spec.group < 0 {
+				issues = append(issues, newIssue(tokFile, spec.node, codeUnmatchedImport, messageArgs{
+					Path: spec.path,
+				}))
+				continue
+			}
+
+			if anchor < 0 {
+				anchor = i
+				anchorPatternI = spec.group
+			}
+		}
+
+		if anchor < 0 {
+			continue
+		}
+
+		var blockPatternI int
+		if c.cfg.RelaxedOrder {
+			if c.cfg.ReportSplitGroups && seen[anchorPatternI] {
+				issues = append(issues, newIssue(tokFile, block[anchor].node, codeSplitGroup, messageArgs{
+					Path:           block[anchor].path,
+					Expected:       groupNames[anchorPatternI],
+					ExpectedNumber: anchorPatternI + 1,
+				}))
+			}
+
+			seen[anchorPatternI] = true
+			blockPatternI = anchorPatternI
+		} else {
+			// the block belongs to the group of its first import, skipping the empty groups in between
+			blockPatternI = anchorPatternI
+			switch {
+			case anchored && anchorPatternI < currPatternI:
+				issues = append(issues, newIssue(tokFile, block[anchor].node, codeGroupOrder, messageArgs{
+					Path:           block[anchor].path,
+					Expected:       groupNames[anchorPatternI],
+					ExpectedNumber: anchorPatternI + 1,
+					Actual:         groupNames[currPatternI],
+					ActualNumber:   currPatternI + 1,
+				}))
+
+				layout[bi].after = currPatternI
+			case c.cfg.ReportSplitGroups && anchored && anchorPatternI == currPatternI:
+				issues = append(issues, newIssue(tokFile, block[anchor].node, codeSplitGroup, messageArgs{
+					Path:           block[anchor].path,
+					Expected:       groupNames[currPatternI],
+					ExpectedNumber: currPatternI + 1,
+				}))
+			default:
+				currPatternI = anchorPatternI
+			}
+		}
+
+		anchored = true
+		layout[bi].group = blockPatternI
+
+		// an import belongs to the first group matching it, even when the pattern of a later group matches it too
+		for _, spec := range block[anchor+1:] {
+			if spec.group == blockPatternI || spec.group < 0 { // the latter already reported as unmatched
+				continue
+			}
+
+			issues = append(issues, newIssue(tokFile, spec.node, codeMixedGroup, messageArgs{
+				Path:           spec.path,
+				Expected:       groupNames[spec.group],
+				ExpectedNumber: spec.group + 1,
+				Actual:         groupNames[blockPatternI],
+				ActualNumber:   blockPatternI + 1,
+			}))
+		}
+
+		if c.cfg.Sorted {
+			for i := 1; i < len(block); i++ {
+				if sortedBefore(exportImport(block[i]), exportImport(block[i-1]), c.style) {
+					issues = append(issues, newIssue(tokFile, block[i].node, codeUnsortedImport, messageArgs{
+						Path:  block[i].path,
+						Other: block[i-1].path,
+					}))
+				}
+			}
+		}
+	}
+
+	for _, group := range c.required {
+		if !hasGroup(blocks, group) {
+			issues = append(issues, newIssue(tokFile, decls[0], codeMissingGroup, messageArgs{
+				Expected:       groupNames[group],
+				ExpectedNumber: group + 1,
+			}))
+		}
+	}
+
+	if len(issues) > 0 && c.cfg.Explain {
+		issues[0].args.Layout = describeLayout(tokFile, blocks, layout, groupNames)
+	}
+
+	// the fixes render a single declaration of blank line separated blocks
+	if src == nil || c.cfg.DeclGroups {
+		return issues, nil
+	}
+
+	// every issue the fix resolves holds it until the disabled and suppressed ones are dropped, see firstRegroupFix
+	var regroup []fix
+	for i := range issues {
+		switch issues[i].code {
+		case codeGroupOrder, codeMixedGroup, codeSplitGroup, codeUnsortedImport:
+		default:
+			continue
+		}
+
+		if regroup == nil {
+			f, ok := regroupFix(tokFile, src, fileNode, decls[0], blocks, groupNames, c.cfg.RelaxedOrder, c.style)
+			if !ok {
+				break
+			}
+
+			regroup = []fix{f}
+		}
+
+		issues[i].fixes = regroup
+	}
+
+	if len(issues) > 0 && c.cfg.Preview > 0 && !importsCgo(decls[0]) {
+		issues[0].args.PreviewLine, issues[0].args.Preview = renderPreview(
+			tokFile, src, decls[0], blocks, groupNames, c.cfg.RelaxedOrder, c.style, c.cfg.Preview,
+		)
+	}
+
+	return issues, nil
+}
+
+// groupBlocks sets the group of every import of blocks.
+func (c *Checker) groupBlocks(blocks [][]importSpec) error {
+	for _, block := range blocks {
+		for i := range block {
+			var err error
+			block[i].group, err = c.matcher.groupOf(block[i].path, c.patterns)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasGroup reports whether an import of blocks belongs to group.
+func hasGroup(blocks [][]importSpec, group int) bool {
+	for _, block := range blocks {
+		for _, spec := range block {
+			if spec.group == group {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parseRequiredGroups returns the indexes of the groups of a Config.RequiredGroups list of group numbers and names.
+func parseRequiredGroups(list string, names []string) ([]int, error) {
+	if list == "" {
+		return nil, nil
+	}
+
+	var required []int
+	for _, ref := range strings.Split(list, ",") {
+		ref = strings.TrimSpace(ref)
+
+		index := -1
+		if n, err := strconv.Atoi(ref); err == nil {
+			if n < 1 || n > len(names) {
+				return nil, fmt.Errorf("%w: required group %d out of the %d groups", ErrConfigInvalid, n, len(names))
+			}
+
+			index = n - 1
+		} else {
+			for i, name := range names {
+				if name == ref {
+					index = i
+					break
+				}
+			}
+
+			if index < 0 {
+				return nil, fmt.Errorf("%w: required group %q is no group number or name", ErrConfigInvalid, ref)
+			}
+		}
+
+		required = append(required, index)
+	}
+
+	return required, nil
+}
+
+func newIssue(tokFile *token.File, node ast.Node, code string, args messageArgs) issue {
+	return issue{
+		pos:  tokFile.Offset(node.Pos()),
+		end:  tokFile.Offset(node.End()),
+		code: code,
+		args: args,
+	}
+}
+
+func getImportDecls(node *ast.File) []*ast.GenDecl {
+	var decls []*ast.GenDecl
+	for _, decl := range node.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT {
+			continue
+		}
+
+		decls = append(decls, genDecl)
+	}
+
+	return decls
+}
+
+// importPath returns the path imported by spec, with the escapes of its string literal resolved.
+func importPath(spec *ast.ImportSpec) string {
+	path, err := strconv.Unquote(spec.Path.Value)
+	if err != nil {
+		return strings.Trim(spec.Path.Value, "\"`")
+	}
+
+	return path
+}
+
+// getDeclBlock returns the imports of decl as a single block, blank lines included. Imports of "C" are left out.
+func getDeclBlock(decl *ast.GenDecl) []importSpec {
+	var block []importSpec
+	for _, s := range decl.Specs {
+		spec := s.(*ast.ImportSpec)
+		if !isCgoImport(spec) {
+			block = append(block, importSpec{path: importPath(spec), node: spec})
+		}
+	}
+
+	return block
+}
+
+// getImportBlocks splits the specs of decl into blocks separated by blank lines, the same way gofmt does. Imports of
+// "C" are left out, and so are their preambles, the lines of which separate no blocks.
+func getImportBlocks(tokFile *token.File, decl *ast.GenDecl) [][]importSpec {
+	var blocks [][]importSpec
+	var currBlock []importSpec
+
+	prevLine := 0  // the last line of the spec before, 0 for none
+	blank := false // whether a blank line precedes the imports of "C" since the last block
+	for _, s := range decl.Specs {
+		spec := s.(*ast.ImportSpec)
+
+		if isCgoImport(spec) {
+			start := spec.Pos()
+			if spec.Doc != nil {
+				start = spec.Doc.Pos()
+			}
+
+			blank = blank || prevLine > 0 && tokFile.Line(start) > prevLine+1
+			prevLine = tokFile.Line(spec.End())
+			continue
+		}
+
+		if len(currBlock) > 0 && (blank || tokFile.Line(spec.Pos()) > prevLine+1) {
+			blocks = append(blocks, currBlock)
+			currBlock = nil
+		}
+
+		blank = false
+		prevLine = tokFile.Line(spec.End())
+		currBlock = append(currBlock, importSpec{
+			path: importPath(spec),
+			node: spec,
+		})
+	}
+
+	return append(blocks, currBlock)
+}
@@ -0,0 +1,1160 @@
+package analyzer_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+const checkerSrc = `package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+`
+
+func TestCheckFiles(t *testing.T) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "fmt:os;time"
+
+	c, err := analyzer.NewChecker(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := c.CheckFiles([]analyzer.NamedSource{
+		{Name: "bad.go", Src: []byte(checkerSrc)},
+		{Name: "broken.go", Src: []byte("package")},
+		{Name: "good.go", Src: []byte("package main\n\nimport \"fmt\"\n")},
+	})
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	bad := results[0]
+	if bad.Err != nil || len(bad.Issues) != 1 {
+		t.Fatalf("expected a single issue in bad.go, got %v, %v", bad.Issues, bad.Err)
+	}
+
+	iss := bad.Issues[0]
+	if iss.Code != "mixed-group" || iss.Pos.Filename != "bad.go" || iss.Pos.Line != 6 || iss.Pos.Column != 2 {
+		t.Errorf("unexpected issue %+v", iss)
+	}
+
+	if iss.End.Column != 8 {
+		t.Errorf("expected the issue to end at column 8, got %d", iss.End.Column)
+	}
+
+	if !errors.Is(results[1].Err, analyzer.ErrParse) {
+		t.Errorf("expected a parse error for broken.go, got %v", results[1].Err)
+	}
+
+	if results[2].Err != nil || len(results[2].Issues) != 0 {
+		t.Errorf("expected no issues in good.go, got %v, %v", results[2].Issues, results[2].Err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "fmt;time"
+	cfg.GroupNames = `format\;text;clock`
+
+	src := "package main\n\nimport (\n\t\"time\"\n\n\t\"fmt\"\n)\n"
+
+	issues, err := analyzer.Check([]byte(src), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(issues) != 1 {
+		t.Fatalf("expected a single issue, got %v", issues)
+	}
+
+	iss := issues[0]
+	if iss.Code != "group-order" || iss.Path != "fmt" || iss.Expected != "format;text" || iss.Pos.Line != 6 {
+		t.Errorf("expected the group-order issue of fmt on line 6, got %+v", iss)
+	}
+
+	cfg.Groups = "("
+	if _, err := analyzer.Check([]byte(src), cfg); !errors.Is(err, analyzer.ErrConfigInvalid) {
+		t.Errorf("expected an invalid configuration error, got %v", err)
+	}
+}
+
+func TestCheckSeverities(t *testing.T) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "fmt:bufio;time"
+	cfg.Sorted = true
+
+	src := "package main\n\nimport (\n\t\"time\"\n\n\t\"fmt\"\n\t\"bufio\"\n)\n"
+
+	errSev, warnSev := analyzer.SeverityError, analyzer.SeverityWarning
+
+	tests := []struct {
+		severity, severities string
+		want                 map[string]string
+	}{
+		{
+			want: map[string]string{"group-order": errSev, "unsorted-import": errSev},
+		},
+		{
+			severities: "unsorted-import=warning",
+			want:       map[string]string{"group-order": errSev, "unsorted-import": warnSev},
+		},
+		{
+			severity:   "warning",
+			severities: "group-order=error",
+			want:       map[string]string{"group-order": errSev, "unsorted-import": warnSev},
+		},
+	}
+
+	for _, tt := range tests {
+		cfg.Severity, cfg.Severities = tt.severity, tt.severities
+
+		issues, err := analyzer.Check([]byte(src), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := make(map[string]string)
+		for _, iss := range issues {
+			got[iss.Code] = iss.Severity
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("-severity %q -severities %q: expected the severities %v, got %v", tt.severity, tt.severities,
+				tt.want, got)
+		}
+	}
+
+	for _, severities := range []string{"group-order=fatal", "group-order"} {
+		cfg.Severity, cfg.Severities = "", severities
+		if _, err := analyzer.Check([]byte(src), cfg); !errors.Is(err, analyzer.ErrConfigInvalid) {
+			t.Errorf("-severities %q: expected an invalid configuration error, got %v", severities, err)
+		}
+	}
+}
+
+func TestFixSource(t *testing.T) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "fmt;time"
+
+	src := []byte("package main\n\nimport (\n\t\"time\"\n\n\t\"fmt\"\n)\n")
+
+	fixed, err := analyzer.FixSource(src, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "package main\n\nimport (\n\t\"fmt\"\n\n\t\"time\"\n)\n"
+	if string(fixed) != want {
+		t.Fatalf("expected the regrouped source\n%s\ngot\n%s", want, fixed)
+	}
+
+	again, err := analyzer.FixSource(fixed, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if &again[0] != &fixed[0] {
+		t.Errorf("expected the conforming source itself, got a copy %q", again)
+	}
+}
+
+func TestFixSourceGofmt(t *testing.T) {
+	// merging the blocks of the standard library puts os before fmt, unless sorted
+	src := []byte("package main\n\nimport (\n\t\"os\"\n\n\t\"github.com/org/lib\"\n\n\t\"fmt\"\n)\n")
+
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "std;.*"
+
+	fixed, err := analyzer.FixSource(src, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	formatted, err := format.Source(fixed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(formatted) != string(fixed) {
+		t.Errorf("expected the fixed source to be left as is by gofmt, got\n%s\ngofmt makes it\n%s", fixed, formatted)
+	}
+
+	cfg.Sort = "none"
+
+	fixed, err = analyzer.FixSource(src, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "package main\n\nimport (\n\t\"os\"\n\t\"fmt\"\n\n\t\"github.com/org/lib\"\n)\n"
+	if string(fixed) != want {
+		t.Errorf("expected the imports in their given order with the none sort order\n%s\ngot\n%s", want, fixed)
+	}
+}
+
+func TestFixSourceRelaxedOrder(t *testing.T) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "std;.*"
+	cfg.RelaxedOrder = true
+
+	src := []byte("package main\n\nimport (\n\t\"github.com/org/y\"\n\n\t\"os\"\n\t\"github.com/org/z\"\n)\n")
+
+	fixed, err := analyzer.FixSource(src, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the third party group comes first, as it does in the source
+	want := "package main\n\nimport (\n\t\"github.com/org/y\"\n\t\"github.com/org/z\"\n\n\t\"os\"\n)\n"
+	if string(fixed) != want {
+		t.Errorf("expected the groups in the order they first appear in\n%s\ngot\n%s", want, fixed)
+	}
+}
+
+func TestFixSourceTrailingComments(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("testdata", "src", "trailing_comments", "trailing.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "std;.*"
+
+	fixed, err := analyzer.FixSource(src, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(fixed) == string(src) {
+		t.Fatal("expected the groups to be swapped")
+	}
+
+	formatted, err := format.Source(fixed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(formatted) != string(fixed) {
+		t.Errorf("expected the trailing comments aligned like gofmt, got\n%s\ngofmt makes it\n%s", fixed, formatted)
+	}
+}
+
+func TestTraceMatch(t *testing.T) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = `std;.* && !(github\.com/org/.* || localmodule);localmodule`
+	cfg.GroupNames = ";third-party"
+	cfg.LocalModule = "github.com/org/app"
+
+	c, err := analyzer.NewChecker(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	group, trace, err := c.TraceMatch("github.com/org/app/sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "group 1:\n" +
+		"  std: does not match\n" +
+		"group 2 \"third-party\":\n" +
+		"  .* && !(github\\.com/org/.* || localmodule): does not match\n" +
+		"    .*: matches\n" +
+		"    !(github\\.com/org/.* || localmodule): does not match\n" +
+		"      github\\.com/org/.* || localmodule: matches\n" +
+		"        github\\.com/org/.*: matches\n" +
+		"        localmodule: matches\n" +
+		"group 3:\n" +
+		"  localmodule: matches\n"
+	if group != 3 || trace != want {
+		t.Errorf("expected group 3 with the trace\n%s\ngot group %d with\n%s", want, group, trace)
+	}
+
+	if group, _, err := c.TraceMatch("github.com/org/lib"); err != nil || group != 0 {
+		t.Errorf("expected no group, got %d, %v", group, err)
+	}
+}
+
+func TestCheckFilesRegroupFix(t *testing.T) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "fmt:os;time"
+
+	c, err := analyzer.NewChecker(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := c.CheckBytes("bad.go", []byte(checkerSrc))
+	if err != nil || len(issues) != 1 || len(issues[0].Fixes) != 1 {
+		t.Fatalf("expected a single issue with a fix, got %+v, %v", issues, err)
+	}
+
+	edits := issues[0].Fixes[0].Edits
+	if len(edits) != 1 || edits[0].Pos.Line != 3 || edits[0].End.Line != 7 {
+		t.Fatalf("expected a single edit of the import declaration, got %+v", edits)
+	}
+
+	fixed := checkerSrc[:edits[0].Pos.Offset] + string(edits[0].NewText) + checkerSrc[edits[0].End.Offset:]
+	if want := "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\n\t\"time\"\n)\n"; fixed != want {
+		t.Errorf("expected the fix to regroup the imports into\n%s\ngot\n%s", want, fixed)
+	}
+}
+
+func TestCheckRegroupFixFiltered(t *testing.T) {
+	// the group-order issue of os comes first, the fix belongs to the mixed-group one of fmt once it is dropped
+	src := "package main\n\nimport (\n\t\"time\"\n\n\t\"os\"%s\n\t\"fmt\"\n)\n"
+
+	for name, tc := range map[string]struct {
+		disable string
+		nolint  string
+		code    string
+	}{
+		"first issue":       {code: "group-order"},
+		"disabled":          {disable: "group-order", code: "mixed-group"},
+		"nolint":            {nolint: " //nolint:goimportgroups", code: "mixed-group"},
+		"disabled rest too": {disable: "group-order,mixed-group"},
+	} {
+		cfg := analyzer.DefaultConfig()
+		cfg.Groups = "fmt;os;time"
+		cfg.Disable = tc.disable
+
+		issues, err := analyzer.Check([]byte(fmt.Sprintf(src, tc.nolint)), cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if tc.code == "" {
+			if len(issues) != 0 {
+				t.Errorf("%s: expected no issues, got %+v", name, issues)
+			}
+
+			continue
+		}
+
+		if len(issues) == 0 || issues[0].Code != tc.code || len(issues[0].Fixes) != 1 {
+			t.Errorf("%s: expected the fix on the first %s issue, got %+v", name, tc.code, issues)
+		}
+
+		for _, iss := range issues[1:] {
+			if len(iss.Fixes) != 0 {
+				t.Errorf("%s: expected the fix on the first issue only, got %+v", name, issues)
+			}
+		}
+	}
+}
+
+func TestCheckFilesCgo(t *testing.T) {
+	const preamble = "// #include <stdio.h>\n"
+
+	for src, codes := range map[string]string{
+		"package main\n\n" + preamble + "import \"C\"\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n":          "",
+		"package main\n\nimport \"fmt\"\n\n" + preamble + "import \"C\"\nimport \"os\"\n":             "multiple-import-decls",
+		"package main\n\nimport \"fmt\"\n\n" + preamble + "import \"C\"\n\n// os\nimport \"os\"\n":    "split-import-decls",
+		"package main\n\nimport (\n\t\"fmt\"\n\t" + preamble + "\t\"C\"\n\t\"os\"\n)\n":               "",
+		"package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\n\t" + preamble + "\t\"C\"\n\t\"time\"\n)\n": "",
+		"package main\n\nimport (\n\t\"fmt\"\n\t\"time\"\n\t" + preamble + "\t\"C\"\n\n\t\"os\"\n)\n": "mixed-group",
+	} {
+		cfg := analyzer.DefaultConfig()
+		cfg.Groups = "fmt:os;time"
+		cfg.ImportPosition = true
+
+		c, err := analyzer.NewChecker(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		issues, err := c.CheckBytes("cgo.go", []byte(src))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for _, iss := range issues {
+			got = append(got, iss.Code)
+
+			for _, f := range iss.Fixes {
+				for _, e := range f.Edits {
+					if bytes.Contains(e.NewText, []byte(`"C"`)) || strings.Contains(src[e.Pos.Offset:e.End.Offset], `"C"`) {
+						t.Errorf("expected the fixes to leave the import of C alone in\n%s\ngot %+v", src, f)
+					}
+				}
+			}
+		}
+
+		if strings.Join(got, ",") != codes {
+			t.Errorf("expected issues %q in\n%s\ngot %q", codes, src, got)
+		}
+	}
+}
+
+func TestCheckFilesGenerated(t *testing.T) {
+	src := "// Code generated by mockgen. DO NOT EDIT.\n\npackage main\n\nimport (\n\t\"time\"\n\n\t\"fmt\"\n)\n"
+
+	for include, count := range map[bool]int{false: 0, true: 1} {
+		cfg := analyzer.DefaultConfig()
+		cfg.Groups = "fmt;time"
+		cfg.IncludeGenerated = include
+
+		c, err := analyzer.NewChecker(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		issues, err := c.CheckBytes("mock.go", []byte(src))
+		if err != nil || len(issues) != count {
+			t.Errorf("expected %d issues with IncludeGenerated %t, got %v, %v", count, include, issues, err)
+		}
+	}
+}
+
+func TestCheckFilesExclude(t *testing.T) {
+	src := []byte("package main\n\nimport (\n\t\"time\"\n\n\t\"fmt\"\n)\n")
+	files := []analyzer.NamedSource{
+		{Name: "vendor/github.com/lib/lib.go", Src: src},
+		{Name: "internal/migrations/0001.go", Src: src},
+		{Name: "cmd/main.go", Src: src},
+		{Name: "cmd/main_test.go", Src: src},
+	}
+
+	for _, exclude := range []struct {
+		syntax string
+		values []string
+	}{
+		{syntax: "regex", values: []string{`vendor/.*;.*/migrations/.*`, `.*_test\.go`}},
+		{syntax: "glob", values: []string{"vendor/**", "migrations/*.go;*_test.go"}},
+	} {
+		cfg := analyzer.DefaultConfig()
+		cfg.Groups = "fmt;time"
+		cfg.PatternSyntax = exclude.syntax
+
+		var flags flag.FlagSet
+		analyzer.BindFlags(&flags, &cfg)
+
+		for _, value := range exclude.values {
+			if err := flags.Set("exclude", value); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		c, err := analyzer.NewChecker(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var checked []string
+		for _, result := range c.CheckFiles(files) {
+			if len(result.Issues) > 0 {
+				checked = append(checked, result.Name)
+			}
+		}
+
+		if got := strings.Join(checked, ","); got != "cmd/main.go" {
+			t.Errorf("expected only cmd/main.go to be checked with %s excludes %q, got %q", exclude.syntax,
+				cfg.Exclude, got)
+		}
+	}
+
+	cfg := analyzer.DefaultConfig()
+	cfg.Exclude = "vendor/(.*"
+
+	_, err := analyzer.NewChecker(cfg)
+	if !errors.Is(err, analyzer.ErrConfigInvalid) {
+		t.Errorf("expected an invalid config error for an invalid exclude pattern, got %v", err)
+	}
+}
+
+func TestCheckFilesExplain(t *testing.T) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "fmt:os;time;strings"
+	cfg.GroupNames = "core;clock"
+	cfg.Explain = true
+
+	c, err := analyzer.NewChecker(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := "package main\n\nimport (\n\t\"time\"\n\t\"os\"\n\t\"sort\"\n\n\t\"fmt\"\n\n\t\"sort\"\n)\n"
+
+	issues, err := c.CheckBytes("main.go", []byte(src))
+	if err != nil || len(issues) < 2 {
+		t.Fatalf("expected several issues, got %v, %v", issues, err)
+	}
+
+	want := "\nblocks of imports:\n" +
+		"\tblock 1 at line 4: group 2 \"clock\", mixed with group 1 \"core\" and no group\n" +
+		"\tblock 2 at line 8: group 1 \"core\", out of order after group 2 \"clock\"\n" +
+		"\tblock 3 at line 10: no group"
+
+	var explained []string
+	for _, iss := range issues {
+		if strings.Contains(iss.Message, "blocks of imports") {
+			explained = append(explained, iss.Message)
+		}
+	}
+
+	if len(explained) != 1 || !strings.HasSuffix(explained[0], want) {
+		t.Errorf("expected a single issue ending with%s\ngot %q", want, explained)
+	}
+}
+
+func TestCheckFilesEscapedPath(t *testing.T) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "fmt;os"
+
+	c, err := analyzer.NewChecker(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := "package main\n\nimport (\n\t\"os\"\n\t\"\\x66mt\"\n)\n"
+	results := c.CheckFiles([]analyzer.NamedSource{{Name: "escaped.go", Src: []byte(src)}})
+
+	issues := results[0].Issues
+	if results[0].Err != nil || len(issues) != 1 || !strings.Contains(issues[0].Message, `import "fmt"`) {
+		t.Errorf("expected the escaped path to match as fmt, got %v, %v", issues, results[0].Err)
+	}
+}
+
+func TestCheckFilesImportComment(t *testing.T) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "fmt;.*"
+	cfg.Comments = ";tool|indirect-tool"
+
+	c, err := analyzer.NewChecker(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := "package main\n\nimport (\n\t\"fmt\" // anything\n\n\t_ \"bytes\" //tool\n\t_ \"errors\"\n)\n"
+	results := c.CheckFiles([]analyzer.NamedSource{{Name: "tools.go", Src: []byte(src)}})
+
+	issues := results[0].Issues
+	if results[0].Err != nil || len(issues) != 2 {
+		t.Fatalf("expected two issues in tools.go, got %v, %v", issues, results[0].Err)
+	}
+
+	if len(issues[0].Fixes) != 1 || string(issues[0].Fixes[0].Edits[0].NewText) != "// tool" {
+		t.Errorf("expected a fix normalizing the comment of bytes, got %+v", issues[0].Fixes)
+	}
+
+	if len(issues[1].Fixes) != 0 {
+		t.Errorf("expected no fix for errors as the pattern is not a literal, got %+v", issues[1].Fixes)
+	}
+
+	for comments, msg := range map[string]string{
+		";tool(": "cannot compile regex tool(: error parsing regexp: missing closing ): `tool(` (comment of group 2)",
+		";;tool": "3 comment patterns for 2 groups",
+	} {
+		cfg.Comments = comments
+
+		_, err := analyzer.NewChecker(cfg)
+		if !errors.Is(err, analyzer.ErrConfigInvalid) || !strings.Contains(err.Error(), msg) {
+			t.Errorf("expected an invalid config error containing %q for %s, got %v", msg, comments, err)
+		}
+	}
+}
+
+func TestCheckFilesStd(t *testing.T) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "std;.*"
+
+	c, err := analyzer.NewChecker(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := "package main\n\nimport (\n\t\"encoding/json\"\n\t\"net/http\"\n\t\"std\"\n\n\t\"golang.org/x/tools/go/analysis\"\n)\n"
+	results := c.CheckFiles([]analyzer.NamedSource{{Name: "std.go", Src: []byte(src)}})
+
+	issues := results[0].Issues
+	if results[0].Err != nil || len(issues) != 1 {
+		t.Fatalf("expected a single issue in std.go, got %v, %v", issues, results[0].Err)
+	}
+
+	if issues[0].Code != "mixed-group" || !strings.Contains(issues[0].Message, `"std"`) {
+		t.Errorf("expected the std path, which is no standard library package, to be in the wrong group, got %s",
+			issues[0].Message)
+	}
+}
+
+func TestCheckStdGoVersion(t *testing.T) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "std;.*"
+
+	// iter joined the standard library in go1.23, the package of the same path belonging to the catch-all group before
+	src := []byte("package main\n\nimport (\n\t\"fmt\"\n\n\t\"github.com/foo/bar\"\n\t\"iter\"\n)\n")
+	for version, issues := range map[string]int{"go1.22": 0, "go1.22.5": 0, "go1.23": 1, "": 1} {
+		cfg.GoVersion = version
+
+		got, err := analyzer.Check(src, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(got) != issues || issues > 0 && (got[0].Code != "mixed-group" || got[0].Path != "iter") {
+			t.Errorf("expected %d mixed-group issues of iter with the Go version %q, got %+v", issues, version, got)
+		}
+	}
+}
+
+func TestCheckFilesSorted(t *testing.T) {
+	src := "package main\n\nimport (\n\t_ \"embed\"\n\t\"Zeta\"\n\t\"alpha\"\n)\n"
+
+	for sort, unsorted := range map[string]string{
+		"":                 "Zeta",
+		"path":             "Zeta",
+		"case-insensitive": "Zeta,alpha",
+		"std-first":        "Zeta",
+	} {
+		cfg := analyzer.DefaultConfig()
+		cfg.Sort = sort
+		cfg.Sorted = true
+		cfg.SinkBlankDot = true
+
+		c, err := analyzer.NewChecker(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		results := c.CheckFiles([]analyzer.NamedSource{{Name: "main.go", Src: []byte(src)}})
+		if results[0].Err != nil {
+			t.Fatal(results[0].Err)
+		}
+
+		var paths []string
+		for _, iss := range results[0].Issues {
+			if iss.Code != "unsorted-import" {
+				t.Errorf("expected only unsorted-import issues with sort %q, got %s", sort, iss.Message)
+			}
+
+			paths = append(paths, iss.Path)
+		}
+
+		if got := strings.Join(paths, ","); got != unsorted {
+			t.Errorf("expected unsorted imports %q with sort %q, got %q", unsorted, sort, got)
+		}
+	}
+}
+
+func TestCheckFilesGroupExpressions(t *testing.T) {
+	src := "package main\n\nimport (\n\t\"github.com/org/app\"\n\t\"github.com/org/legacy/db\"\n\t\"github.com/other/lib\"\n)\n"
+
+	for groups, unexpected := range map[string]string{
+		"github.com/org/.* && !github.com/org/legacy/.*;.*":     "github.com/org/legacy/db,github.com/other/lib",
+		"!(github.com/org/legacy/.* || github.com/other/.*);.*": "github.com/org/legacy/db,github.com/other/lib",
+		"github.com/(org|x)/app || github.com/other/.*:.*;.*":   "",
+		"github.com/org/.*, !github.com/org/legacy/.* : std;.*": "github.com/org/legacy/db,github.com/other/lib",
+		"github.com/(org|other)/[a-z]+;.*":                      "github.com/org/legacy/db",
+		`github.com/[a-z]{3\,5}/app;.*`:                         "github.com/org/legacy/db,github.com/other/lib",
+		`github.com/org/(app|a\;b)\:?;.*`:                       "github.com/org/legacy/db,github.com/other/lib",
+	} {
+		cfg := analyzer.DefaultConfig()
+		cfg.Groups = groups
+
+		c, err := analyzer.NewChecker(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		results := c.CheckFiles([]analyzer.NamedSource{{Name: "main.go", Src: []byte(src)}})
+		if results[0].Err != nil {
+			t.Fatal(results[0].Err)
+		}
+
+		var paths []string
+		for _, iss := range results[0].Issues {
+			paths = append(paths, iss.Path)
+		}
+
+		if got := strings.Join(paths, ","); got != unexpected {
+			t.Errorf("expected issues for %q with %s, got %q", unexpected, groups, got)
+		}
+	}
+
+	for groups, msg := range map[string]string{
+		"(fmt && os;.*": `invalid group pattern "(fmt && os": missing ) to close ( at column 1`,
+		"fmt && ;.*":    `invalid group pattern "fmt && ": missing pattern at column 8`,
+		"fmt) || os":    `invalid group pattern "fmt) || os": unexpected ), no ( to close at column 4`,
+		"(fmt || os) time": `invalid group pattern "(fmt || os) time": unexpected "time" after ), expected an operator` +
+			` at column 13`,
+		"fmt || [a-z":    "cannot compile regex [a-z: error parsing regexp: missing closing ]: `[a-z` (group 1)",
+		"std && !(":      `invalid group pattern "std && !(": missing pattern at column 10`,
+		"fmt;std;(os":    `invalid group pattern "(os": missing ) to close ( at column 1 (group 3)`,
+		"localmodule;.*": "the localmodule keyword needs a go.mod",
+	} {
+		cfg := analyzer.DefaultConfig()
+		cfg.Groups = groups
+
+		_, err := analyzer.NewChecker(cfg)
+		if !errors.Is(err, analyzer.ErrConfigInvalid) || !strings.Contains(err.Error(), msg) {
+			t.Errorf("expected an invalid config error containing %q for %s, got %v", msg, groups, err)
+		}
+	}
+}
+
+func TestCheckFilesGlob(t *testing.T) {
+	src := "package main\n\nimport (\n\t\"github.com/org\"\n\t\"github.com/org/app\"\n\t\"github.com/org/legacy/db\"\n\n" +
+		"\t\"github.com/other/lib\"\n\t\"github.com/other/lib/v2\"\n)\n"
+
+	for groups, unexpected := range map[string]string{
+		"github.com/org/**;github.com/*/lib:github.com/*/lib/v?": "",
+		"github.com/org/*;github.com/other/**":                   "github.com/org,github.com/org/legacy/db",
+		"github.com/o[!r]*/**;github.com/org/**":                 "github.com/other/lib",
+		"github.com/org/**, !github.com/org/legacy/**;**":        "github.com/org/legacy/db",
+	} {
+		cfg := analyzer.DefaultConfig()
+		cfg.Groups = groups
+		cfg.PatternSyntax = analyzer.PatternSyntaxGlob
+
+		c, err := analyzer.NewChecker(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		results := c.CheckFiles([]analyzer.NamedSource{{Name: "main.go", Src: []byte(src)}})
+		if results[0].Err != nil {
+			t.Fatal(results[0].Err)
+		}
+
+		var paths []string
+		for _, iss := range results[0].Issues {
+			paths = append(paths, iss.Path)
+		}
+
+		if got := strings.Join(paths, ","); got != unexpected {
+			t.Errorf("expected issues for %q with %s, got %q", unexpected, groups, got)
+		}
+	}
+
+	cfg := analyzer.DefaultConfig()
+	cfg.PatternSyntax = "wildcard"
+
+	_, err := analyzer.NewChecker(cfg)
+	if !errors.Is(err, analyzer.ErrConfigInvalid) {
+		t.Errorf("expected an invalid config error for an unknown pattern syntax, got %v", err)
+	}
+}
+
+func TestCheckFilesPreset(t *testing.T) {
+	src := "package main\n\nimport (\n\t\"fmt\"\n\n\t\"golang.org/x/mod\"\n\n\t\"github.com/org/lib\"\n\n\t\"github.com/org/app/sub\"\n)\n"
+
+	for _, tc := range []struct {
+		preset string
+		issues int
+	}{
+		{preset: "std-thirdparty-org-local", issues: 0},
+		// the packages of the organization belong to the third party group, split in two blocks
+		{preset: "std-thirdparty-local", issues: 1},
+	} {
+		cfg := analyzer.DefaultConfig()
+		cfg.Preset = tc.preset
+		cfg.LocalModule = "github.com/org/app/v2"
+		cfg.ReportSplitGroups = true
+
+		issues, err := analyzer.Check([]byte(strings.ReplaceAll(src, "org/app/sub", "org/app/v2/sub")), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(issues) != tc.issues {
+			t.Errorf("expected %d issues with the preset %s, got %v", tc.issues, tc.preset, issues)
+		}
+	}
+
+	cfg := analyzer.DefaultConfig()
+	cfg.Preset = "std-thirdparty-org-local"
+	cfg.LocalModule = "app"
+	if _, err := analyzer.NewChecker(cfg); !errors.Is(err, analyzer.ErrConfigInvalid) {
+		t.Errorf("expected an invalid configuration error for a module without organization, got %v", err)
+	}
+
+	cfg.Preset = "missing"
+	if _, err := analyzer.NewChecker(cfg); !errors.Is(err, analyzer.ErrConfigInvalid) {
+		t.Errorf("expected an invalid configuration error for an unknown preset, got %v", err)
+	}
+}
+
+func TestCheckPresetMixedStd(t *testing.T) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Preset = "std-thirdparty"
+
+	// the catch-all third party pattern matches fmt too, which still belongs to the std group
+	for _, tc := range []struct {
+		imports, path string
+	}{
+		{imports: "\t\"github.com/foo/bar\"\n\t\"fmt\"\n", path: "fmt"},
+		{imports: "\t\"fmt\"\n\t\"github.com/foo/bar\"\n", path: "github.com/foo/bar"},
+	} {
+		issues, err := analyzer.Check([]byte("package main\n\nimport (\n"+tc.imports+")\n"), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(issues) != 1 || issues[0].Code != "mixed-group" || issues[0].Path != tc.path || len(issues[0].Fixes) != 1 {
+			t.Errorf("expected a mixed-group issue of %s with a fix for the imports\n%s\ngot %+v", tc.path, tc.imports,
+				issues)
+		}
+	}
+}
+
+func TestCheckCatchAllOrder(t *testing.T) {
+	// the catch-all pattern of the last group matches the std imports too, which still belong to the first group
+	for _, tc := range []struct {
+		name    string
+		relaxed bool
+		imports string
+		want    string
+	}{
+		{
+			name:    "reversed blocks",
+			imports: "\t\"github.com/foo/bar\"\n\n\t\"fmt\"\n",
+			want:    "group-order fmt",
+		},
+		{
+			name:    "reversed mixed block",
+			imports: "\t\"github.com/foo/bar\"\n\t\"fmt\"\n",
+			want:    "mixed-group fmt",
+		},
+		{
+			name:    "relaxed reversed blocks",
+			relaxed: true,
+			imports: "\t\"github.com/foo/bar\"\n\n\t\"fmt\"\n",
+		},
+		{
+			name:    "relaxed reversed mixed block",
+			relaxed: true,
+			imports: "\t\"github.com/foo/bar\"\n\t\"fmt\"\n",
+			want:    "mixed-group fmt",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := analyzer.DefaultConfig()
+			cfg.Groups = "std;.*"
+			cfg.RelaxedOrder = tc.relaxed
+			cfg.Explain = true
+
+			issues, err := analyzer.Check([]byte("package main\n\nimport (\n"+tc.imports+")\n"), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, iss := range issues {
+				got = append(got, iss.Code+" "+iss.Path)
+			}
+
+			if strings.Join(got, ", ") != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+
+			if tc.want == "group-order fmt" && !strings.Contains(issues[0].Message, "out of order after group 2") {
+				t.Errorf("expected the layout to tell the block is out of order, got %s", issues[0].Message)
+			}
+		})
+	}
+}
+
+func TestCheckFilesPolicy(t *testing.T) {
+	analyzer.RegisterPolicy("test-fmt-os-time", func(cfg *analyzer.Config) {
+		cfg.Groups = "fmt:os;time"
+	})
+
+	cfg := analyzer.DefaultConfig()
+	cfg.Policies = "test-fmt-os-time"
+
+	c, err := analyzer.NewChecker(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := c.CheckFiles([]analyzer.NamedSource{{Name: "bad.go", Src: []byte(checkerSrc)}})
+	if results[0].Err != nil || len(results[0].Issues) != 1 || results[0].Issues[0].Code != "mixed-group" {
+		t.Errorf("expected the policy groups to apply, got %v, %v", results[0].Issues, results[0].Err)
+	}
+
+	cfg.Policies = "test-fmt-os-time,unknown"
+
+	_, err = analyzer.NewChecker(cfg)
+	if !errors.Is(err, analyzer.ErrConfigInvalid) {
+		t.Errorf("expected a configuration error for an unknown policy, got %v", err)
+	}
+}
+
+func TestCheckDir(t *testing.T) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "fmt:os;time"
+
+	c, err := analyzer.NewChecker(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := fstest.MapFS{
+		"src/bad.go":              {Data: []byte(checkerSrc)},
+		"src/sub/good.go":         {Data: []byte("package sub\n\nimport \"fmt\"\n")},
+		"src/sub/README.md":       {Data: []byte("# sub\n")},
+		"src/testdata/bad.go":     {Data: []byte(checkerSrc)},
+		"src/.hidden/bad.go":      {Data: []byte(checkerSrc)},
+		"other/bad.go":            {Data: []byte(checkerSrc)},
+		"src/_ignored/nothing.go": {Data: []byte("package")},
+	}
+
+	results, err := c.CheckDir(fsys, "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 || results[0].Name != "src/bad.go" || results[1].Name != "src/sub/good.go" {
+		t.Fatalf("expected results for src/bad.go and src/sub/good.go, got %+v", results)
+	}
+
+	if len(results[0].Issues) != 1 || len(results[1].Issues) != 0 {
+		t.Errorf("expected a single issue in src/bad.go, got %+v", results)
+	}
+
+	_, err = c.CheckDir(fsys, "missing")
+	if !errors.Is(err, analyzer.ErrIO) {
+		t.Errorf("expected an IO error for a missing directory, got %v", err)
+	}
+}
+
+func TestGroupFlags(t *testing.T) {
+	values, err := analyzer.GroupFlags([]analyzer.ConfigGroup{
+		{Pattern: "std", Name: "std;lib", Required: true},
+		{Pattern: ".*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"groups": "std;.*", "group-names": `std\;lib`, "required-groups": "1"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("expected the flags %v, got %v", want, values)
+	}
+
+	if _, err := analyzer.GroupFlags([]analyzer.ConfigGroup{{Name: "std"}}); !errors.Is(err, analyzer.ErrConfigInvalid) {
+		t.Errorf("expected an invalid configuration error for a group without pattern, got %v", err)
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, analyzer.ConfigFileName)
+
+	data := "groups:\n  - pattern: fmt\n  - pattern: time\n    comment: needed\n    name: clock;wall\nmessages: messages.json\n" +
+		"preview: 3\nsorted: true\nexclude:\n  - vendor/.*\n  - a;b\n"
+	if err := os.WriteFile(name, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := analyzer.FindConfigFile(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found != name {
+		t.Fatalf("expected %s, got %s", name, found)
+	}
+
+	cfg, err := analyzer.LoadConfigFile(found, analyzer.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Groups != "fmt;time" || cfg.Comments != ";needed" || cfg.GroupNames != `;clock\;wall` || cfg.Preview != 3 {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+
+	if cfg.Exclude != `vendor/.*;a\;b` {
+		t.Errorf("expected the exclude list to be joined with its semicolons escaped, got %s", cfg.Exclude)
+	}
+
+	if cfg.Messages != filepath.Join(dir, "messages.json") {
+		t.Errorf("expected the messages path to be resolved against %s, got %s", dir, cfg.Messages)
+	}
+
+	var flags flag.FlagSet
+	flagCfg := analyzer.DefaultConfig()
+	analyzer.BindFlags(&flags, &flagCfg)
+
+	if err := flags.Parse([]string{"-preview", "5", "-sorted=false"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// -sorted=false is the default value, but set explicitly
+	overridden := analyzer.OverrideConfig(cfg, &flags)
+	if overridden.Preview != 5 || overridden.Sorted || overridden.Groups != "fmt;time" {
+		t.Errorf("expected only the preview and sorted to be overridden, got %+v", overridden)
+	}
+
+	// the drivers set the values of the flags of the analyzer directly
+	a := analyzer.NewAnalyzer()
+	if err := a.Flags.Lookup("sorted").Value.Set("false"); err != nil {
+		t.Fatal(err)
+	}
+
+	overridden = analyzer.OverrideConfig(cfg, &a.Flags)
+	if overridden.Preview != 3 || overridden.Sorted {
+		t.Errorf("expected only sorted to be overridden, got %+v", overridden)
+	}
+
+	if err := os.WriteFile(name, []byte("group: fmt\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = analyzer.LoadConfigFile(name, analyzer.DefaultConfig())
+	if !errors.Is(err, analyzer.ErrConfigInvalid) {
+		t.Errorf("expected an invalid config error for an unknown key, got %v", err)
+	}
+}
+
+// forbiddenImportRule reports the imports of paths starting with prefix, suggesting to delete them.
+type forbiddenImportRule struct {
+	prefix string
+}
+
+func (r forbiddenImportRule) Name() string {
+	return "test-forbidden-import"
+}
+
+func (r forbiddenImportRule) Inspect(block analyzer.ImportBlock, file analyzer.FileInfo) []analyzer.Finding {
+	var findings []analyzer.Finding
+	for i, spec := range block.Specs {
+		if !strings.HasPrefix(block.Paths[i], r.prefix) {
+			continue
+		}
+
+		pos, end := file.Fset.Position(spec.Pos()), file.Fset.Position(spec.End())
+		findings = append(findings, analyzer.Finding{
+			Pos:     pos,
+			End:     end,
+			Message: fmt.Sprintf("package %s may not import %s", file.Package, block.Paths[i]),
+			Fixes:   []analyzer.Fix{{Message: "delete the import", Edits: []analyzer.Edit{{Pos: pos, End: end}}}},
+		})
+	}
+
+	return findings
+}
+
+func TestCheckFilesRule(t *testing.T) {
+	analyzer.RegisterRule(forbiddenImportRule{prefix: "example.com/analytics"})
+
+	cfg := analyzer.DefaultConfig()
+
+	c, err := analyzer.NewChecker(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := "package payments\n\nimport (\n\t\"fmt\"\n\t\"example.com/analytics/sdk\"\n)\n"
+	results := c.CheckFiles([]analyzer.NamedSource{{Name: "payments.go", Src: []byte(src)}})
+
+	issues := results[0].Issues
+	if results[0].Err != nil || len(issues) != 1 {
+		t.Fatalf("expected a single issue in payments.go, got %v, %v", issues, results[0].Err)
+	}
+
+	iss := issues[0]
+	if iss.Code != "test-forbidden-import" || iss.Message != "package payments may not import example.com/analytics/sdk" ||
+		iss.Pos.Line != 5 || len(iss.Fixes) != 1 || iss.Fixes[0].Message != "delete the import" {
+		t.Errorf("unexpected issue %+v", iss)
+	}
+
+	cfg.Disable = "test-forbidden-import"
+
+	c, err = analyzer.NewChecker(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results = c.CheckFiles([]analyzer.NamedSource{{Name: "payments.go", Src: []byte(src)}})
+	if len(results[0].Issues) != 0 {
+		t.Errorf("expected the disabled rule not to report, got %v", results[0].Issues)
+	}
+}
+
+func BenchmarkCheckFiles(b *testing.B) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "fmt:os;time"
+
+	c, err := analyzer.NewChecker(cfg)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	files := make([]analyzer.NamedSource, 1000)
+	for i := range files {
+		files[i] = analyzer.NamedSource{Name: fmt.Sprintf("file%d.go", i), Src: []byte(checkerSrc)}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.CheckFiles(files)
+	}
+}
+
+// BenchmarkNewChecker measures the checker created for every package analyzed, the regexes of which are compiled
+// once per run.
+func BenchmarkNewChecker(b *testing.B) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = `std;github\.com/org/.* && !github\.com/org/legacy/.*;(golang\.org|google\.golang\.org)/.*;.*`
+	cfg.Comments = ";;;tool|indirect"
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := analyzer.NewChecker(cfg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func FuzzCheckBytes(f *testing.F) {
+	f.Add([]byte(checkerSrc))
+	f.Add([]byte("package main\n\nimport \"fmt\"\n\nimport \"os\"\n"))
+	f.Add([]byte("package main\n\nimport (\n\t// \"old\"\n\t_ \"\\x66mt\" //x\n)\n"))
+	f.Add([]byte("package"))
+
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "fmt:os;time,t.*;.*"
+	cfg.Comments = ";tool"
+	cfg.ImportPosition = true
+	cfg.ReportEmptyDecls = true
+	cfg.Preview = 5
+
+	c, err := analyzer.NewChecker(cfg)
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	f.Fuzz(func(t *testing.T, src []byte) {
+		issues, err := c.CheckBytes("fuzz.go", src)
+		if err != nil && len(issues) != 0 {
+			t.Errorf("expected no issues along with an error, got %v", issues)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// Imports is the result of the analyzer: the imports of the files of a package classified into the configured groups,
+// for analyzers requiring it, such as dependency policy checks, to use instead of parsing and matching imports again.
+type Imports struct {
+	Files []FileImports
+}
+
+// FileImports holds the imports of a file as its canonical import declaration lists them.
+type FileImports struct {
+	// Name is the name of the file on disk.
+	Name string
+	// Groups holds the non-empty groups in the configured order, named by their name or pattern, followed by the
+	// imports that match no group in a group without a name. The imports are sorted as configured.
+	Groups []Group
+}
+
+// classify returns the imports of fileNode classified into the groups of the checker.
+func (c *Checker) classify(fset *token.FileSet, fileNode *ast.File) ([]Group, error) {
+	tokFile := fset.File(fileNode.Pos())
+
+	var blocks [][]importSpec
+	for _, decl := range getImportDecls(fileNode) {
+		for _, block := range getImportBlocks(tokFile, decl) {
+			for i := range block {
+				var err error
+				block[i].group, err = c.matcher.groupOf(block[i].path, c.patterns)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			blocks = append(blocks, block)
+		}
+	}
+
+	groups := exportGroups(expectedBlocks(blocks, len(c.patterns), false), c.names)
+	for i := range groups {
+		groups[i].Imports = sortImports(groups[i].Imports, c.style)
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,142 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/scanner"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// commentedOutImport matches the text of a line comment holding an import spec, optionally aliased and followed by a
+// comment of its own.
+var commentedOutImport = regexp.MustCompile(`^//\s*(?:[\w.]+\s+)?("[^"]+"|` + "`[^`]+`" + `)\s*(?://.*)?$`)
+
+// findCommentIssues reports the imports of the groups with a comment pattern whose trailing comment is missing, does
+// not match the pattern, or is not written as a single line comment. The suggested fix normalizes the comment if its
+// text matches, or sets it if the pattern is a literal.
+func (c *Checker) findCommentIssues(tokFile *token.File, decls []*ast.GenDecl) ([]issue, error) {
+	if len(c.commentPatterns) == 0 {
+		return nil, nil
+	}
+
+	var issues []issue
+	for _, decl := range decls {
+		for _, s := range decl.Specs {
+			spec := s.(*ast.ImportSpec)
+			path := importPath(spec)
+
+			group, err := c.matcher.groupOf(path, c.patterns)
+			if err != nil {
+				return nil, err
+			}
+
+			if group < 0 || group >= len(c.commentPatterns) || c.commentPatterns[group] == "" {
+				continue
+			}
+
+			pattern := c.commentPatterns[group]
+
+			var text string
+			if spec.Comment != nil {
+				text = strings.TrimSpace(spec.Comment.Text())
+			}
+
+			matches, err := c.commentMatcher.match(text, pattern)
+			if err != nil {
+				return nil, err
+			}
+
+			if matches && spec.Comment != nil && len(spec.Comment.List) == 1 && spec.Comment.List[0].Text == "// "+text {
+				continue
+			}
+
+			iss := newIssue(tokFile, spec, codeImportComment, messageArgs{
+				Path:           path,
+				ExpectedNumber: group + 1,
+				Pattern:        pattern,
+			})
+
+			if !matches && regexp.QuoteMeta(pattern) == pattern {
+				text, matches = pattern, true
+			}
+
+			if matches {
+				iss.args.Other = "// " + text
+
+				e := edit{pos: tokFile.Offset(spec.End()), end: tokFile.Offset(spec.End()), newText: " " + iss.args.Other}
+				if spec.Comment != nil {
+					e = edit{pos: tokFile.Offset(spec.Comment.Pos()), end: tokFile.Offset(spec.Comment.End()), newText: iss.args.Other}
+				}
+
+				iss.fixes = []fix{{message: msgFixImportComment, edits: []edit{e}}}
+			}
+
+			issues = append(issues, iss)
+		}
+	}
+
+	return issues, nil
+}
+
+// findCommentedOutImports reports the line comments inside the parentheses of the import declarations that hold an
+// import spec, suggesting to delete their line.
+func findCommentedOutImports(tokFile *token.File, src []byte, decls []*ast.GenDecl) []issue {
+	var issues []issue
+	for _, decl := range decls {
+		if !decl.Lparen.IsValid() || !decl.Rparen.IsValid() {
+			continue
+		}
+
+		start, end := tokFile.Offset(decl.Lparen)+1, tokFile.Offset(decl.Rparen)
+		segment := token.NewFileSet().AddFile("", -1, end-start)
+
+		var s scanner.Scanner
+		s.Init(segment, src[start:end], nil, scanner.ScanComments)
+
+		prevLine := 1 // the segment starts on the line of the parenthesis
+		for {
+			pos, tok, lit := s.Scan()
+			if tok == token.EOF {
+				break
+			}
+
+			line := segment.Line(pos)
+			if tok == token.COMMENT && line != prevLine {
+				if m := commentedOutImport.FindStringSubmatch(lit); m != nil {
+					issues = append(issues, commentedOutImportIssue(tokFile, start+segment.Offset(pos), lit, m[1]))
+				}
+			}
+
+			prevLine = line
+		}
+	}
+
+	return issues
+}
+
+func commentedOutImportIssue(tokFile *token.File, offset int, comment, quotedPath string) issue {
+	path, err := strconv.Unquote(quotedPath)
+	if err != nil {
+		path = quotedPath
+	}
+
+	line := tokFile.Line(tokFile.Pos(offset))
+
+	lineEnd := tokFile.Size()
+	if line < tokFile.LineCount() {
+		lineEnd = tokFile.Offset(tokFile.LineStart(line + 1))
+	}
+
+	return issue{
+		pos:  offset,
+		end:  offset + len(comment),
+		code: codeCommentedOutImport,
+		args: messageArgs{Path: path},
+		fixes: []fix{{
+			message: msgFixCommentedOutImport,
+			edits:   []edit{{pos: tokFile.Offset(tokFile.LineStart(line)), end: lineEnd}},
+		}},
+	}
+}
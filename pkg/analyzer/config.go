@@ -0,0 +1,336 @@
+package analyzer
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileName is the name of the configuration file looked up in the directory of the checked package and its
+// parents.
+const ConfigFileName = ".goimportgroups.yaml"
+
+// fileConfig is the content of a configuration file. Its keys are the names of the flags of the options.
+type fileConfig struct {
+	Groups []ConfigGroup `yaml:"groups"`
+	// Severities maps rule codes to their severity, as the code=severity pairs of the severities flag.
+	Severities map[string]string `yaml:"severities"`
+
+	Preset                 *string  `yaml:"preset"`
+	DocsURL                *string  `yaml:"docs-url"`
+	MaxIssuesPerFile       *int     `yaml:"max-issues-per-file"`
+	CollapseIdentical      *bool    `yaml:"collapse-identical"`
+	Disable                *string  `yaml:"disable"`
+	Severity               *string  `yaml:"severity"`
+	Preview                *int     `yaml:"preview"`
+	Explain                *bool    `yaml:"explain"`
+	Messages               *string  `yaml:"messages"`
+	Sort                   *string  `yaml:"sort"`
+	Sorted                 *bool    `yaml:"sorted"`
+	SinkBlankDot           *bool    `yaml:"sink-blank-dot"`
+	AlignAliases           *bool    `yaml:"align-aliases"`
+	NormalizeQuotes        *bool    `yaml:"normalize-quotes"`
+	RemoveRedundantAliases *bool    `yaml:"remove-redundant-aliases"`
+	ImportPosition         *bool    `yaml:"import-position"`
+	Policies               *string  `yaml:"policies"`
+	Exclude                []string `yaml:"exclude"`
+	IncludeGenerated       *bool    `yaml:"include-generated"`
+	ReportEmptyDecls       *bool    `yaml:"report-empty-decls"`
+	ReportSplitGroups      *bool    `yaml:"report-split-groups"`
+	RelaxedOrder           *bool    `yaml:"relaxed-order"`
+	DeclGroups             *bool    `yaml:"decl-groups"`
+	HeaderComments         *bool    `yaml:"header-comments"`
+	PatternSyntax          *string  `yaml:"pattern-syntax"`
+	LocalModule            *string  `yaml:"local-module"`
+	GoVersion              *string  `yaml:"go-version"`
+}
+
+// ConfigGroup is a group of a configuration file, or of the settings of golangci-lint.
+type ConfigGroup struct {
+	// Name is the name of the group, as in the group-names flag.
+	Name string `yaml:"name" json:"name"`
+	// Required makes the group required, as in the required-groups flag.
+	Required bool `yaml:"required" json:"required"`
+	// Pattern is the boolean expression of regex patterns of the group, as in the groups flag.
+	Pattern string `yaml:"pattern" json:"pattern"`
+	// Comment is the pattern the trailing comments of the imports of the group must match, as in the comments flag.
+	Comment string `yaml:"comment" json:"comment"`
+}
+
+// GroupFlags returns the values of the groups, comments, group-names and required-groups flags listing groups, keyed
+// by the names of the flags, leaving out the empty ones. It fails if a group has no pattern.
+func GroupFlags(groups []ConfigGroup) (map[string]string, error) {
+	patterns := make([]string, len(groups))
+	comments := make([]string, len(groups))
+	names := make([]string, len(groups))
+	var required []string
+	for i, group := range groups {
+		if group.Pattern == "" {
+			return nil, fmt.Errorf("%w: group %d has no pattern", ErrConfigInvalid, i+1)
+		}
+
+		patterns[i], comments[i], names[i] = group.Pattern, group.Comment, group.Name
+		if group.Required {
+			required = append(required, strconv.Itoa(i+1))
+		}
+	}
+
+	values := make(map[string]string)
+	for name, value := range map[string]string{
+		"groups":          joinEscaped(patterns),
+		"comments":        joinEscaped(comments),
+		"group-names":     joinEscaped(names),
+		"required-groups": strings.Join(required, ","),
+	} {
+		if value != "" {
+			values[name] = value
+		}
+	}
+
+	return values, nil
+}
+
+// FindConfigFile returns the path of the ConfigFileName file in dir or the closest of its parents, or "" if there is
+// none.
+func FindConfigFile(dir string) (string, error) {
+	return findUp(dir, ConfigFileName)
+}
+
+// FindModulePath returns the module path of the go.mod file in dir or the closest of its parents, or "" if there is
+// none.
+func FindModulePath(dir string) (string, error) {
+	name, err := findUp(dir, "go.mod")
+	if err != nil || name == "" {
+		return "", err
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrIO, err)
+	}
+
+	path := modfile.ModulePath(data)
+	if path == "" {
+		return "", fmt.Errorf("%w: %s has no module directive", ErrConfigInvalid, name)
+	}
+
+	return path, nil
+}
+
+// FindGoVersion returns the Go version of the go directive of the go.mod file in dir or the closest of its parents,
+// like go1.22, or "" if there is none.
+func FindGoVersion(dir string) (string, error) {
+	name, err := findUp(dir, "go.mod")
+	if err != nil || name == "" {
+		return "", err
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrIO, err)
+	}
+
+	f, err := modfile.ParseLax(name, data, nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrConfigInvalid, err)
+	}
+
+	if f.Go == nil {
+		return "", nil
+	}
+
+	return "go" + f.Go.Version, nil
+}
+
+// findUp returns the path of the file base in dir or the closest of its parents, or "" if there is none.
+func findUp(dir, base string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		name := filepath.Join(dir, base)
+
+		_, err := os.Stat(name)
+		if err == nil {
+			return name, nil
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %w", ErrIO, err)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+
+		dir = parent
+	}
+}
+
+// LoadConfigFile returns cfg with the options set in the YAML configuration file name applied. Relative paths of
+// message files are resolved against the directory of the configuration file.
+func LoadConfigFile(name string, cfg Config) (Config, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return Config{}, fmt.Errorf("%w: %w", ErrIO, err)
+	}
+
+	var fc fileConfig
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&fc); err != nil && !errors.Is(err, io.EOF) {
+		return Config{}, fmt.Errorf("%w: cannot parse config file %s: %w", ErrConfigInvalid, name, err)
+	}
+
+	if len(fc.Groups) > 0 {
+		values, err := GroupFlags(fc.Groups)
+		if err != nil {
+			return Config{}, fmt.Errorf("config file %s: %w", name, err)
+		}
+
+		cfg.Groups, cfg.Comments = values["groups"], values["comments"]
+		cfg.GroupNames, cfg.RequiredGroups = values["group-names"], values["required-groups"]
+	}
+
+	set(&cfg.Preset, fc.Preset)
+	set(&cfg.DocsURL, fc.DocsURL)
+	set(&cfg.MaxIssuesPerFile, fc.MaxIssuesPerFile)
+	set(&cfg.CollapseIdentical, fc.CollapseIdentical)
+	set(&cfg.Disable, fc.Disable)
+	set(&cfg.Severity, fc.Severity)
+	if len(fc.Severities) > 0 {
+		codes := make([]string, 0, len(fc.Severities))
+		for code := range fc.Severities {
+			codes = append(codes, code)
+		}
+
+		sort.Strings(codes)
+
+		pairs := make([]string, len(codes))
+		for i, code := range codes {
+			pairs[i] = code + "=" + fc.Severities[code]
+		}
+
+		cfg.Severities = strings.Join(pairs, ",")
+	}
+	set(&cfg.Preview, fc.Preview)
+	set(&cfg.Explain, fc.Explain)
+	set(&cfg.Messages, fc.Messages)
+	set(&cfg.Sort, fc.Sort)
+	set(&cfg.Sorted, fc.Sorted)
+	set(&cfg.SinkBlankDot, fc.SinkBlankDot)
+	set(&cfg.AlignAliases, fc.AlignAliases)
+	set(&cfg.NormalizeQuotes, fc.NormalizeQuotes)
+	set(&cfg.RemoveRedundantAliases, fc.RemoveRedundantAliases)
+	set(&cfg.ImportPosition, fc.ImportPosition)
+	set(&cfg.Policies, fc.Policies)
+	if len(fc.Exclude) > 0 {
+		cfg.Exclude = joinEscaped(fc.Exclude)
+	}
+
+	set(&cfg.IncludeGenerated, fc.IncludeGenerated)
+	set(&cfg.ReportEmptyDecls, fc.ReportEmptyDecls)
+	set(&cfg.ReportSplitGroups, fc.ReportSplitGroups)
+	set(&cfg.RelaxedOrder, fc.RelaxedOrder)
+	set(&cfg.DeclGroups, fc.DeclGroups)
+	set(&cfg.HeaderComments, fc.HeaderComments)
+	set(&cfg.PatternSyntax, fc.PatternSyntax)
+	set(&cfg.LocalModule, fc.LocalModule)
+	set(&cfg.GoVersion, fc.GoVersion)
+
+	if fc.Messages != nil && *fc.Messages != "" && !filepath.IsAbs(*fc.Messages) {
+		cfg.Messages = filepath.Join(filepath.Dir(name), *fc.Messages)
+	}
+
+	return cfg, nil
+}
+
+// joinEscaped joins items into a semicolon separated list, escaping the semicolons within them as \;, leaving out the
+// empty items at the end.
+func joinEscaped(items []string) string {
+	for len(items) > 0 && items[len(items)-1] == "" {
+		items = items[:len(items)-1]
+	}
+
+	escaped := make([]string, len(items))
+	for i, item := range items {
+		escaped[i] = strings.ReplaceAll(item, ";", `\;`)
+	}
+
+	return strings.Join(escaped, ";")
+}
+
+func set[T any](field *T, value *T) {
+	if value != nil {
+		*field = *value
+	}
+}
+
+// OverrideConfig returns cfg with the flags of flags bound to a Config that were set explicitly applied, so they take
+// precedence over a configuration file even when set to their default.
+func OverrideConfig(cfg Config, flags *flag.FlagSet) Config {
+	var bound flag.FlagSet
+	BindFlags(&bound, &cfg)
+
+	explicit := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	flags.VisitAll(func(f *flag.Flag) {
+		if v, ok := f.Value.(*setValue); ok && v.set {
+			explicit[f.Name] = true
+		}
+
+		if bound.Lookup(f.Name) == nil || !explicit[f.Name] {
+			return
+		}
+
+		// the values come from flags of the same types, so they parse
+		_ = bound.Set(f.Name, f.Value.String())
+	})
+
+	return cfg
+}
+
+// setValue is a flag value recording whether it was set, for the flags set without going through their flag set.
+type setValue struct {
+	flag.Value
+	set bool
+}
+
+func (v *setValue) Set(s string) error {
+	v.set = true
+
+	return v.Value.Set(s)
+}
+
+func (v *setValue) String() string {
+	// the flag package calls String on a zero value to tell the default values apart
+	if v.Value == nil {
+		return ""
+	}
+
+	return v.Value.String()
+}
+
+func (v *setValue) IsBoolFlag() bool {
+	b, ok := v.Value.(interface{ IsBoolFlag() bool })
+
+	return ok && b.IsBoolFlag()
+}
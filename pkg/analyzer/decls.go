@@ -0,0 +1,171 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/scanner"
+	"go/token"
+	"strings"
+)
+
+// findSplitDecl reports decl as split from the import declaration before it if a comment or directive separates them,
+// which hides the second declaration from readers of the first. The suggested fix merges the specs of decl into the
+// first declaration of the file as a block of their own, leaving the comments in between in place. The declaration
+// before may be one importing "C" left out of decls, so that its preamble splits nothing.
+func findSplitDecl(tokFile *token.File, src []byte, fileNode *ast.File, decls []*ast.GenDecl, i int) (issue, bool) {
+	prev := previousDecl(fileNode, decls[i])
+	start, end := tokFile.Offset(prev.End()), tokFile.Offset(decls[i].Pos())
+	segment := token.NewFileSet().AddFile("", -1, end-start)
+
+	var s scanner.Scanner
+	s.Init(segment, src[start:end], nil, scanner.ScanComments)
+
+	for {
+		_, tok, _ := s.Scan()
+		if tok == token.EOF {
+			return issue{}, false
+		}
+
+		if tok == token.COMMENT {
+			break
+		}
+	}
+
+	iss := newIssue(tokFile, decls[i], codeSplitImportDecls, messageArgs{Line: tokFile.Line(decls[0].Pos())})
+	iss.fixes = []fix{mergeDeclFix(tokFile, src, decls[0], decls[i])}
+
+	return iss, true
+}
+
+// mergeDeclFix returns the fix moving the specs of decl to the end of first, separated by a blank line, and deleting
+// decl along with the blank line above it.
+func mergeDeclFix(tokFile *token.File, src []byte, first, decl *ast.GenDecl) fix {
+	line := func(pos token.Pos) int {
+		return tokFile.Line(pos)
+	}
+
+	lineStart := func(line int) int {
+		if line > tokFile.LineCount() {
+			return tokFile.Size()
+		}
+
+		return tokFile.Offset(tokFile.LineStart(line))
+	}
+
+	var specs string
+	if decl.Lparen.IsValid() {
+		specs = string(src[lineStart(line(decl.Lparen)+1):lineStart(line(decl.Rparen))])
+	} else {
+		specs = "\t" + string(src[tokFile.Offset(decl.Specs[0].Pos()):lineStart(line(decl.End())+1)])
+	}
+
+	var insert edit
+	if first.Lparen.IsValid() {
+		pos := lineStart(line(first.Rparen))
+		insert = edit{pos: pos, end: pos, newText: "\n" + specs}
+	} else {
+		spec := string(src[tokFile.Offset(first.Specs[0].Pos()):tokFile.Offset(first.End())])
+		insert = edit{
+			pos:     tokFile.Offset(first.Pos()),
+			end:     tokFile.Offset(first.End()),
+			newText: "import (\n\t" + spec + "\n\n" + specs + ")",
+		}
+	}
+
+	return fix{message: msgFixSplitImportDecls, edits: []edit{insert, removeDecl(tokFile, first, decl)}}
+}
+
+// removeDecl returns the edit deleting the lines of decl along with the blank line above it, unless that line
+// belongs to prev, the declaration before it.
+func removeDecl(tokFile *token.File, prev, decl *ast.GenDecl) edit {
+	lineStart := func(line int) int {
+		if line > tokFile.LineCount() {
+			return tokFile.Size()
+		}
+
+		return tokFile.Offset(tokFile.LineStart(line))
+	}
+
+	line := tokFile.Line(decl.Pos())
+
+	remove := edit{pos: lineStart(line), end: lineStart(tokFile.Line(decl.End()) + 1)}
+	if above := line - 1; above > tokFile.Line(prev.End()) && lineStart(above+1)-lineStart(above) == 1 {
+		remove.pos = lineStart(above)
+	}
+
+	return remove
+}
+
+// mergeDeclsFix returns the fix replacing the first of decls with the import declaration the blocks of all of them
+// are expected to form, and deleting the others. There is no fix if a declaration imports "C", whose preamble has to
+// stay right above it, or holds comments that are neither the doc nor the trailing comment of an import.
+func mergeDeclsFix(
+	tokFile *token.File,
+	fileNode *ast.File,
+	decls []*ast.GenDecl,
+	blocks [][]importSpec,
+	groupNames []string,
+	relaxed bool,
+	style Style,
+) (fix, bool) {
+	for _, decl := range decls {
+		if hasFloatingComments(fileNode, decl) {
+			return fix{}, false
+		}
+	}
+
+	if importsCgo(decls...) {
+		return fix{}, false
+	}
+
+	style.RemoveRedundantAliases = false
+
+	lines, err := renderBlockLines(exportGroups(expectedBlocks(blocks, len(groupNames), relaxed), groupNames), style)
+	if err != nil {
+		return fix{}, false
+	}
+
+	edits := []edit{{
+		pos:     tokFile.Offset(decls[0].Pos()),
+		end:     tokFile.Offset(decls[0].End()),
+		newText: strings.Join(lines, "\n"),
+	}}
+
+	for i, decl := range decls[1:] {
+		edits = append(edits, removeDecl(tokFile, decls[i], decl))
+	}
+
+	return fix{message: msgFixMergeImportDecls, edits: edits}, true
+}
+
+// findEmptyDecls reports the import declarations without specs, like `import ()`, suggesting to delete their lines
+// along with the blank line below them. It returns the remaining declarations.
+func findEmptyDecls(tokFile *token.File, decls []*ast.GenDecl) ([]issue, []*ast.GenDecl) {
+	var issues []issue
+	var rest []*ast.GenDecl
+	for _, decl := range decls {
+		if len(decl.Specs) > 0 {
+			rest = append(rest, decl)
+			continue
+		}
+
+		line := tokFile.Line(decl.End()) + 1
+		end := tokFile.Size()
+		if line <= tokFile.LineCount() {
+			end = tokFile.Offset(tokFile.LineStart(line))
+		}
+
+		if line < tokFile.LineCount() && tokFile.Offset(tokFile.LineStart(line+1))-end == 1 {
+			end++
+		}
+
+		iss := newIssue(tokFile, decl, codeEmptyImportDecl, messageArgs{})
+		iss.fixes = []fix{{
+			message: msgFixEmptyImportDecl,
+			edits:   []edit{{pos: tokFile.Offset(tokFile.LineStart(tokFile.Line(decl.Pos()))), end: end}},
+		}}
+
+		issues = append(issues, iss)
+	}
+
+	return issues, rest
+}
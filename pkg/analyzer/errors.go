@@ -0,0 +1,14 @@
+package analyzer
+
+import "errors"
+
+// The errors returned by the checks wrap one of these, so callers can tell the causes of failures apart with
+// errors.Is.
+var (
+	// ErrConfigInvalid is the cause of failures due to the configuration, like a group pattern that does not compile.
+	ErrConfigInvalid = errors.New("invalid configuration")
+	// ErrParse is the cause of failures to parse a source file.
+	ErrParse = errors.New("cannot parse source")
+	// ErrIO is the cause of failures to read a file.
+	ErrIO = errors.New("cannot read file")
+)
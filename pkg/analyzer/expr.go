@@ -0,0 +1,335 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// expr is a parsed group pattern: a boolean expression of regex patterns matched against whole import paths.
+//
+//	or    = and { ("||" | ":") and }
+//	and   = unary { ("&&" | ",") unary }
+//	unary = "!" unary | "(" or ")" | regex | "std" | "localmodule"
+//
+// A regex is anything up to the next && or ||, or the next , : or closing parenthesis outside of its own parentheses,
+// brackets and escapes, so it keeps its meaning, e.g. (foo|bar)/.* is a regex rather than a parenthesized expression.
+// \, \: and \; stand for the characters themselves in regexes, e.g. [a-z]{2\,3}. Spaces around operators are ignored.
+type expr interface {
+	matches(s string) bool
+}
+
+type (
+	// regexExpr holds the pattern the regex was compiled from, as written, for traces.
+	regexExpr struct {
+		re      *regexp.Regexp
+		pattern string
+	}
+	notExpr     struct{ x expr }
+	andExpr     struct{ l, r expr }
+	orExpr      struct{ l, r expr }
+	keywordExpr struct {
+		keyword string
+		match   func(s string) bool
+	}
+)
+
+func (e regexExpr) matches(s string) bool   { return e.re.MatchString(s) }
+func (e notExpr) matches(s string) bool     { return !e.x.matches(s) }
+func (e andExpr) matches(s string) bool     { return e.l.matches(s) && e.r.matches(s) }
+func (e orExpr) matches(s string) bool      { return e.l.matches(s) || e.r.matches(s) }
+func (e keywordExpr) matches(s string) bool { return e.match(s) }
+
+// exprSyntaxError is a syntax error in a group pattern.
+type exprSyntaxError struct {
+	pattern string
+	offset  int
+	msg     string
+}
+
+func (e *exprSyntaxError) Error() string {
+	return fmt.Sprintf("invalid group pattern %q: %s at column %d", e.pattern, e.msg, e.offset+1)
+}
+
+// exprParser parses a group pattern, compiling its regexes with the matcher.
+type exprParser struct {
+	m       *matcher
+	pattern string
+	pos     int
+}
+
+// parseExpr parses pattern, whose regexes m compiles.
+func parseExpr(m *matcher, pattern string) (expr, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return m.regexExpr(pattern)
+	}
+
+	p := &exprParser{m: m, pattern: pattern}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if p.pos < len(p.pattern) {
+		if p.pattern[p.pos] == ')' {
+			return nil, p.errorf("unexpected ), no ( to close")
+		}
+
+		return nil, p.errorf("unexpected %q, expected an operator", p.pattern[p.pos:])
+	}
+
+	return e, nil
+}
+
+func (p *exprParser) parseOr() (expr, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.operator("||", ":") {
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		l = orExpr{l: l, r: r}
+	}
+
+	return l, nil
+}
+
+func (p *exprParser) parseAnd() (expr, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.operator("&&", ",") {
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		l = andExpr{l: l, r: r}
+	}
+
+	return l, nil
+}
+
+func (p *exprParser) parseUnary() (expr, error) {
+	p.skipSpaces()
+
+	if p.pos >= len(p.pattern) {
+		return nil, p.errorf("missing pattern")
+	}
+
+	switch p.pattern[p.pos] {
+	case '!':
+		p.pos++
+
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return notExpr{x: x}, nil
+	case '(':
+		start := p.pos
+
+		e, groupErr := p.parseGroup()
+		if groupErr == nil {
+			if p.atOperand() {
+				return e, nil
+			}
+
+			groupErr = p.errorf("unexpected %q after ), expected an operator", p.pattern[p.pos:])
+		}
+
+		// the parenthesis may open a regex group instead, like in (foo|bar)/.*
+		p.pos = start
+
+		e, err := p.parseRegex()
+		if err == nil && p.atOperand() {
+			return e, nil
+		}
+
+		return nil, groupErr
+	case ')':
+		return nil, p.errorf("missing pattern before )")
+	}
+
+	return p.parseRegex()
+}
+
+// parseGroup parses a parenthesized expression.
+func (p *exprParser) parseGroup() (expr, error) {
+	open := p.pos
+	p.pos++
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if p.pos >= len(p.pattern) || p.pattern[p.pos] != ')' {
+		p.pos = open
+		return nil, p.errorf("missing ) to close (")
+	}
+
+	p.pos++
+
+	return e, nil
+}
+
+// parseRegex parses a regex or a keyword.
+func (p *exprParser) parseRegex() (expr, error) {
+	start := p.pos
+	depth := 0
+
+scan:
+	for p.pos < len(p.pattern) {
+		switch c := p.pattern[p.pos]; {
+		case c == '\\':
+			p.pos++
+		case c == '[':
+			// a ] right after the bracket, or after its negation, is part of the class
+			p.pos++
+			if p.pos < len(p.pattern) && p.pattern[p.pos] == '^' {
+				p.pos++
+			}
+
+			if p.pos < len(p.pattern) && p.pattern[p.pos] == ']' {
+				p.pos++
+			}
+
+			for p.pos < len(p.pattern) && p.pattern[p.pos] != ']' {
+				if p.pattern[p.pos] == '\\' {
+					p.pos++
+				}
+
+				p.pos++
+			}
+		case c == '(':
+			depth++
+		case c == ')' && depth == 0:
+			break scan
+		case c == ')':
+			depth--
+		case p.at("&&") || p.at("||") || depth == 0 && (c == ',' || c == ':'):
+			break scan
+		}
+
+		p.pos++
+	}
+
+	if p.pos > len(p.pattern) {
+		p.pos = len(p.pattern)
+	}
+
+	pattern := strings.TrimSpace(p.pattern[start:p.pos])
+	switch pattern {
+	case "":
+		p.pos = start
+		return nil, p.errorf("missing pattern")
+	case stdKeyword:
+		return p.m.stdExpr(), nil
+	case localModuleKeyword:
+		return p.m.localModuleExpr()
+	}
+
+	return p.m.regexExpr(unescapePattern(pattern))
+}
+
+// atOperand reports whether the parser stands at the end of an operand: an operator, a closing parenthesis or the end
+// of the pattern.
+func (p *exprParser) atOperand() bool {
+	p.skipSpaces()
+
+	if p.pos >= len(p.pattern) {
+		return true
+	}
+
+	c := p.pattern[p.pos]
+
+	return c == ')' || c == ',' || c == ':' || p.at("&&") || p.at("||")
+}
+
+// operator consumes the first of ops found at the position of the parser, if any.
+func (p *exprParser) operator(ops ...string) bool {
+	p.skipSpaces()
+
+	for _, op := range ops {
+		if p.at(op) {
+			p.pos += len(op)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *exprParser) at(s string) bool {
+	return strings.HasPrefix(p.pattern[p.pos:], s)
+}
+
+func (p *exprParser) skipSpaces() {
+	for p.pos < len(p.pattern) && p.pattern[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *exprParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %w", ErrConfigInvalid, &exprSyntaxError{
+		pattern: p.pattern,
+		offset:  p.pos,
+		msg:     fmt.Sprintf(format, args...),
+	})
+}
+
+// splitPatterns splits patterns at the semicolons not escaped as \;.
+func splitPatterns(patterns string) []string {
+	var split []string
+
+	start := 0
+	for i := 0; i < len(patterns); i++ {
+		switch patterns[i] {
+		case '\\':
+			i++
+		case ';':
+			split = append(split, patterns[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(split, patterns[start:])
+}
+
+// unescapePattern replaces the \, \: and \; escapes of pattern by the characters themselves, leaving the other
+// escapes to the regex.
+func unescapePattern(pattern string) string {
+	if !strings.Contains(pattern, "\\") {
+		return pattern
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '\\' && i+1 < len(pattern) {
+			if next := pattern[i+1]; next == ',' || next == ':' || next == ';' {
+				b.WriteByte(next)
+				i++
+				continue
+			}
+
+			b.WriteByte(pattern[i])
+			i++
+		}
+
+		b.WriteByte(pattern[i])
+	}
+
+	return b.String()
+}
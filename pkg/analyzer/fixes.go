@@ -0,0 +1,185 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// typeSafeFixes returns the fixes of the issue at pos that keep the file compiling according to the type information of
+// the pass. The fixes changing or removing aliases are dropped when the type information is missing, so the issue is
+// only reported.
+func typeSafeFixes(
+	c *Checker,
+	pass *analysis.Pass,
+	file *ast.File,
+	pos token.Pos,
+	iss issue,
+	fixes []analysis.SuggestedFix,
+) ([]analysis.SuggestedFix, error) {
+	switch iss.code {
+	case codeRedundantAlias:
+		spec := importSpecAt(file, pos)
+		if spec == nil || spec.Name == nil {
+			return nil, nil
+		}
+
+		// removing the alias leaves the references untouched, which is only safe if it is the package name indeed
+		pkgName := importedPkgName(pass.TypesInfo, spec)
+		if pkgName == nil || pkgName.Imported().Name() != spec.Name.Name {
+			return nil, nil
+		}
+	case codeDuplicateImport:
+		msg, err := c.messages.render(msgFixDuplicateImport, iss.args)
+		if err != nil {
+			return nil, err
+		}
+
+		if fix, ok := consolidateFix(pass, file, pos, msg); ok {
+			fixes = append(fixes, fix)
+		}
+	}
+
+	return fixes, nil
+}
+
+// consolidateFix suggests removing the duplicate import at pos, rewriting the references to its name in file to the
+// name of the import of the same path that is kept. It returns false if the type information needed to do so safely
+// is missing, or if a rewritten reference would resolve to something else.
+func consolidateFix(pass *analysis.Pass, file *ast.File, pos token.Pos, message string) (analysis.SuggestedFix, bool) {
+	var kept *ast.ImportSpec
+	dup := importSpecAt(file, pos)
+	if dup == nil {
+		return analysis.SuggestedFix{}, false
+	}
+
+	for _, spec := range file.Imports {
+		if spec != dup && importPath(spec) == importPath(dup) {
+			kept = spec
+			break
+		}
+	}
+
+	if kept == nil || isDotImport(dup) || isDotImport(kept) {
+		return analysis.SuggestedFix{}, false
+	}
+
+	if isBlankImport(kept) {
+		// the blank import is the one not needed
+		dup, kept = kept, dup
+	}
+
+	fix := analysis.SuggestedFix{Message: message, TextEdits: []analysis.TextEdit{removeSpec(pass.Fset, file, dup)}}
+	if isBlankImport(dup) {
+		return fix, true
+	}
+
+	dupObj, keptObj := importedPkgName(pass.TypesInfo, dup), importedPkgName(pass.TypesInfo, kept)
+	if dupObj == nil || keptObj == nil {
+		return analysis.SuggestedFix{}, false
+	}
+
+	renames, ok := renameReferences(pass, file, dupObj, keptObj)
+	if !ok {
+		return analysis.SuggestedFix{}, false
+	}
+
+	fix.TextEdits = append(fix.TextEdits, renames...)
+
+	return fix, true
+}
+
+// renameReferences returns the edits renaming the references to from in file to the name of to, or false if to is
+// shadowed at any of them.
+func renameReferences(pass *analysis.Pass, file *ast.File, from, to *types.PkgName) ([]analysis.TextEdit, bool) {
+	var edits []analysis.TextEdit
+	for ident, obj := range pass.TypesInfo.Uses {
+		if obj != from || ident.Pos() < file.Pos() || ident.End() > file.End() {
+			continue
+		}
+
+		scope := pass.Pkg.Scope().Innermost(ident.Pos())
+		if scope == nil {
+			return nil, false
+		}
+
+		if _, resolved := scope.LookupParent(to.Name(), ident.Pos()); resolved != to {
+			return nil, false
+		}
+
+		edits = append(edits, analysis.TextEdit{Pos: ident.Pos(), End: ident.End(), NewText: []byte(to.Name())})
+	}
+
+	return edits, true
+}
+
+func importSpecAt(file *ast.File, pos token.Pos) *ast.ImportSpec {
+	for _, spec := range file.Imports {
+		if spec.Pos() == pos {
+			return spec
+		}
+	}
+
+	return nil
+}
+
+func importedPkgName(info *types.Info, spec *ast.ImportSpec) *types.PkgName {
+	if info == nil {
+		return nil
+	}
+
+	var obj types.Object
+	if spec.Name != nil {
+		obj = info.Defs[spec.Name]
+	} else {
+		obj = info.Implicits[spec]
+	}
+
+	pkgName, _ := obj.(*types.PkgName)
+
+	return pkgName
+}
+
+// removeSpec returns the edit deleting the lines of spec, including its comments, or the whole declaration if spec is
+// its only spec.
+func removeSpec(fset *token.FileSet, file *ast.File, spec *ast.ImportSpec) analysis.TextEdit {
+	var node ast.Node = spec
+	start, end := spec.Pos(), spec.End()
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if ok && len(genDecl.Specs) == 1 && genDecl.Specs[0] == spec {
+			node = genDecl
+			start, end = genDecl.Pos(), genDecl.End()
+		}
+	}
+
+	if spec.Doc != nil && node == spec {
+		start = spec.Doc.Pos()
+	}
+
+	if spec.Comment != nil && node == spec {
+		end = spec.Comment.End()
+	}
+
+	tokFile := fset.File(start)
+	start = tokFile.LineStart(tokFile.Line(start))
+
+	if line := tokFile.Line(end); line < tokFile.LineCount() {
+		end = tokFile.LineStart(line + 1)
+	} else {
+		end = token.Pos(tokFile.Base() + tokFile.Size())
+	}
+
+	return analysis.TextEdit{Pos: start, End: end}
+}
+
+func isDotImport(spec *ast.ImportSpec) bool {
+	return spec.Name != nil && spec.Name.Name == "."
+}
+
+func isBlankImport(spec *ast.ImportSpec) bool {
+	return spec.Name != nil && spec.Name.Name == "_"
+}
@@ -0,0 +1,43 @@
+package analyzer
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// retryWait is the wait before the first retry of a failed read, doubled before each of the next ones.
+const retryWait = 50 * time.Millisecond
+
+// readFromFS returns a function reading files by their name on disk from fsys, with the volume and the leading
+// separator of absolute names removed, as fs.FS requires.
+func readFromFS(fsys fs.FS) func(name string) ([]byte, error) {
+	return func(name string) ([]byte, error) {
+		name = strings.TrimPrefix(filepath.ToSlash(name[len(filepath.VolumeName(name)):]), "/")
+
+		return fs.ReadFile(fsys, name)
+	}
+}
+
+// retryReads returns read retrying up to retries times on errors other than missing files and denied permissions,
+// which transient failures of networked and virtual filesystems are.
+func retryReads(read func(name string) ([]byte, error), retries int) func(name string) ([]byte, error) {
+	if retries <= 0 {
+		return read
+	}
+
+	return func(name string) ([]byte, error) {
+		wait := retryWait
+		for attempt := 0; ; attempt++ {
+			src, err := read(name)
+			if err == nil || attempt == retries || errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+				return src, err
+			}
+
+			time.Sleep(wait)
+			wait *= 2
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Pattern syntaxes of Config.PatternSyntax.
+const (
+	PatternSyntaxRegex = "regex"
+	PatternSyntaxGlob  = "glob"
+)
+
+// isGlobSyntax reports whether syntax, a Config.PatternSyntax, is the glob one.
+func isGlobSyntax(syntax string) (bool, error) {
+	switch syntax {
+	case "", PatternSyntaxRegex:
+		return false, nil
+	case PatternSyntaxGlob:
+		return true, nil
+	default:
+		return false, fmt.Errorf("%w: unknown pattern syntax %q, expected %s or %s", ErrConfigInvalid, syntax,
+			PatternSyntaxRegex, PatternSyntaxGlob)
+	}
+}
+
+// globRegex returns the regex matching the import paths glob matches: * matches any characters but slashes, **
+// matches any characters, a trailing /** also matching the path before it, ? matches any character but a slash and
+// [...] matches a character class, like in gitignore files. Other characters, and the escaped ones, like \*, match
+// themselves.
+func globRegex(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if strings.HasPrefix(glob[i:], "**") {
+				b.WriteString(".*")
+				i++
+				continue
+			}
+
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+
+			class := glob[i+1 : i+1+end]
+			if negated, ok := strings.CutPrefix(class, "!"); ok {
+				class = "^" + negated
+			}
+
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+			}
+
+			b.WriteString(regexp.QuoteMeta(string(glob[i])))
+		case '/':
+			if glob[i+1:] == "**" {
+				b.WriteString("(?:/.*)?")
+				return b.String()
+			}
+
+			b.WriteByte(c)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return b.String()
+}
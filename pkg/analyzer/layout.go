@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+)
+
+// blockLayout is the group a block of imports was matched to, and the group of the block before it if the block is
+// out of order, -1 for none.
+type blockLayout struct {
+	group int
+	after int
+}
+
+func newLayout(n int) []blockLayout {
+	layout := make([]blockLayout, n)
+	for i := range layout {
+		layout[i] = blockLayout{group: -1, after: -1}
+	}
+
+	return layout
+}
+
+// describeLayout describes the group each of blocks was matched to, one line per block, along with the imports of
+// other groups mixed into it and whether it is out of order, like:
+//
+//	block 2 at line 7: group 2 "third-party", mixed with group 3 "internal"
+func describeLayout(tokFile *token.File, blocks [][]importSpec, layout []blockLayout, groupNames []string) string {
+	group := func(i int) string {
+		return fmt.Sprintf("group %d %q", i+1, groupNames[i])
+	}
+
+	var lines []string
+	for i, block := range blocks {
+		if len(block) == 0 {
+			continue
+		}
+
+		line := fmt.Sprintf("\tblock %d at line %d: ", len(lines)+1, tokFile.Line(block[0].node.Pos()))
+		if layout[i].group < 0 {
+			lines = append(lines, line+"no group")
+			continue
+		}
+
+		line += group(layout[i].group)
+
+		var mixed []string
+		seen := map[int]bool{layout[i].group: true}
+		unmatched := false
+		for _, spec := range block {
+			if spec.group < 0 {
+				unmatched = true
+				continue
+			}
+
+			if !seen[spec.group] {
+				seen[spec.group] = true
+				mixed = append(mixed, group(spec.group))
+			}
+		}
+
+		if unmatched {
+			mixed = append(mixed, "no group")
+		}
+
+		if len(mixed) > 0 {
+			line += ", mixed with " + strings.Join(mixed, " and ")
+		}
+
+		if layout[i].after >= 0 {
+			line += ", out of order after " + group(layout[i].after)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,23 @@
+package analyzer
+
+import (
+	"io"
+	"log/slog"
+	"os"
+)
+
+// newLogger returns the logger for troubleshooting runs, writing structured logs to stderr at the level selected by
+// -v (info) or -vv (debug), and discarding them otherwise.
+func newLogger(verbose, veryVerbose bool) *slog.Logger {
+	var w io.Writer = os.Stderr
+	level := slog.LevelInfo
+
+	switch {
+	case veryVerbose:
+		level = slog.LevelDebug
+	case !verbose:
+		w = io.Discard
+	}
+
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+}
@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// localModuleKeyword is the group pattern matching the packages of the local module.
+const localModuleKeyword = "localmodule"
+
+// compiledRegexps caches the regexes compiled by all matchers, keyed by their source, so that the checkers created for
+// every package of a run compile each of them only once. Regexps are safe for concurrent use.
+var compiledRegexps sync.Map
+
+// matcher evaluates group patterns, parsing each pattern and compiling each regex only once.
+type matcher struct {
+	exprs       map[string]expr
+	regexps     map[string]*regexp.Regexp
+	localModule string
+	goVersion   string
+	// glob makes the patterns globs rather than regexes.
+	glob bool
+}
+
+func newMatcher(localModule, goVersion string, glob bool) *matcher {
+	return &matcher{
+		exprs:       make(map[string]expr),
+		regexps:     make(map[string]*regexp.Regexp),
+		localModule: localModule,
+		goVersion:   goVersion,
+		glob:        glob,
+	}
+}
+
+// groupOf returns the index of the first group pattern matching importPath, or -1 if none does.
+func (m *matcher) groupOf(importPath string, groupPatterns []string) (int, error) {
+	for i, pattern := range groupPatterns {
+		matches, err := m.match(importPath, pattern)
+		if err != nil {
+			return 0, err
+		}
+
+		if matches {
+			return i, nil
+		}
+	}
+
+	return -1, nil
+}
+
+func (m *matcher) match(s string, pattern string) (bool, error) {
+	e, err := m.expr(pattern)
+	if err != nil {
+		return false, err
+	}
+
+	return e.matches(s), nil
+}
+
+func (m *matcher) expr(pattern string) (expr, error) {
+	if e, ok := m.exprs[pattern]; ok {
+		return e, nil
+	}
+
+	e, err := parseExpr(m, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	m.exprs[pattern] = e
+
+	return e, nil
+}
+
+func (m *matcher) regexExpr(pattern string) (expr, error) {
+	if re, ok := m.regexps[pattern]; ok {
+		return regexExpr{re: re, pattern: pattern}, nil
+	}
+
+	source := pattern
+	if m.glob {
+		source = globRegex(pattern)
+	}
+
+	anchored := fmt.Sprintf("^(?:%s)$", source)
+	if re, ok := compiledRegexps.Load(anchored); ok {
+		m.regexps[pattern] = re.(*regexp.Regexp)
+		return regexExpr{re: re.(*regexp.Regexp), pattern: pattern}, nil
+	}
+
+	re, err := regexp.Compile(anchored)
+	if err != nil {
+		// the error of the bare source quotes the fragment of the pattern at fault rather than the anchored regex
+		if _, bareErr := regexp.Compile(source); bareErr != nil {
+			err = bareErr
+		}
+
+		if m.glob {
+			return nil, fmt.Errorf("%w: cannot compile glob %s: %w", ErrConfigInvalid, pattern, err)
+		}
+
+		return nil, fmt.Errorf("%w: cannot compile regex %s: %w", ErrConfigInvalid, pattern, err)
+	}
+
+	compiledRegexps.Store(anchored, re)
+	m.regexps[pattern] = re
+
+	return regexExpr{re: re, pattern: pattern}, nil
+}
+
+func (m *matcher) stdExpr() expr {
+	goVersion := m.goVersion
+
+	return keywordExpr{keyword: stdKeyword, match: func(s string) bool {
+		return isStd(s, goVersion)
+	}}
+}
+
+func (m *matcher) localModuleExpr() (expr, error) {
+	if m.localModule == "" {
+		return nil, fmt.Errorf("%w: the %s keyword needs a go.mod or a local module path", ErrConfigInvalid,
+			localModuleKeyword)
+	}
+
+	module := m.localModule
+
+	return keywordExpr{keyword: localModuleKeyword, match: func(s string) bool {
+		return s == module || strings.HasPrefix(s, module+"/")
+	}}, nil
+}
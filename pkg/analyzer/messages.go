@@ -0,0 +1,189 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"text/template"
+)
+
+const (
+	msgPreview               = "preview"
+	msgLayout                = "layout"
+	msgFixRedundantAlias     = "fix-redundant-alias"
+	msgFixDuplicateImport    = "fix-duplicate-import"
+	msgFixImportComment      = "fix-import-comment"
+	msgFixCommentedOutImport = "fix-commented-out-import"
+	msgFixImportPosition     = "fix-import-position"
+	msgFixSplitImportDecls   = "fix-split-import-decls"
+	msgFixMergeImportDecls   = "fix-merge-import-decls"
+	msgFixEmptyImportDecl    = "fix-empty-import-decl"
+	msgFixRegroup            = "fix-regroup"
+)
+
+// defaultMessages holds the templates of all user-facing messages, keyed by rule code. The templates are executed
+// with messageArgs and can be overridden per deployment through the -messages flag.
+var defaultMessages = map[string]string{
+	codeMultipleImportDecls: `multiple import declarations: imports are already declared at line {{.Line}}`,
+	codeGroupOrder: `import {{printf "%q" .Path}} belongs to group {{printf "%q" .Expected}} (group {{.ExpectedNumber}})` +
+		` but appears after group {{.ActualNumber}} ({{printf "%q" .Actual}})`,
+	codeMixedGroup: `import {{printf "%q" .Path}} belongs to group {{printf "%q" .Expected}} (group {{.ExpectedNumber}})` +
+		` but appears in group {{.ActualNumber}} ({{printf "%q" .Actual}})`,
+	codeSplitGroup: `import {{printf "%q" .Path}} starts another block of group {{printf "%q" .Expected}}` +
+		` (group {{.ExpectedNumber}}) instead of joining the block before it`,
+	codeMissingGroup:     `imports lack the required group {{printf "%q" .Expected}} (group {{.ExpectedNumber}})`,
+	codeUnsortedImport:   `import {{printf "%q" .Path}} is not sorted: it goes before {{printf "%q" .Other}}`,
+	codeUnmatchedImport:  `import {{printf "%q" .Path}} does not belong to any group`,
+	codeIssueLimit:       `{{.Count}} more issues in this file are not reported`,
+	codeGlobalIssueLimit: `the limit of {{.Count}} issues is reached, further issues are not reported`,
+	msgPreview:           "expected imports from line {{.PreviewLine}}:\n{{.Preview}}",
+	msgLayout:            "blocks of imports:\n{{.Layout}}",
+	codeRedundantAlias:   `import {{printf "%q" .Path}} is aliased to its package name {{.Name}}`,
+	msgFixRedundantAlias: `remove alias {{.Name}}`,
+	codeDuplicateImport: `import {{printf "%q" .Path}} as {{.Name}} duplicates its import as {{.Other}}` +
+		` at line {{.Line}}`,
+	msgFixDuplicateImport: `remove the duplicate import and refer to the package as {{.Other}}`,
+	codeImportComment: `import {{printf "%q" .Path}} in group {{.ExpectedNumber}} must have a comment matching` +
+		` {{printf "%q" .Pattern}}`,
+	msgFixImportComment:      `set the comment to {{printf "%q" .Other}}`,
+	codeCommentedOutImport:   `import {{printf "%q" .Path}} is commented out`,
+	msgFixCommentedOutImport: `delete the commented-out import`,
+	codeImportPosition:       `imports should follow the package clause at line {{.Line}}`,
+	msgFixImportPosition:     `move the imports below the package clause`,
+	codeSplitImportDecls:     `import declaration is split by a comment from the imports declared at line {{.Line}}`,
+	msgFixSplitImportDecls:   `merge the imports into the declaration at line {{.Line}}`,
+	msgFixMergeImportDecls:   `merge the import declarations into the one at line {{.Line}}`,
+	codeEmptyImportDecl:      `import declaration without imports`,
+	msgFixEmptyImportDecl:    `delete the empty import declaration`,
+	msgFixRegroup:            `regroup the imports`,
+}
+
+type messageArgs struct {
+	Path           string
+	Name           string
+	Other          string
+	Pattern        string
+	Expected       string
+	ExpectedNumber int
+	Actual         string
+	ActualNumber   int
+	Line           int
+	Count          int
+	PreviewLine    int
+	Preview        string
+	Layout         string
+	Message        string
+}
+
+type catalog map[string]*template.Template
+
+// loadCatalog parses the default messages, along with the ones of rules, overriding them with the ones found in the
+// JSON object stored in filename, if any.
+func loadCatalog(filename string, rules []Rule) (catalog, error) {
+	messages := make(map[string]string, len(defaultMessages)+len(rules))
+	for key, text := range defaultMessages {
+		messages[key] = text
+	}
+
+	for _, r := range rules {
+		messages[r.Name()] = "{{.Message}}"
+	}
+
+	if filename != "" {
+		overrides, err := readMessages(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		for key, text := range overrides {
+			if _, ok := messages[key]; !ok {
+				return nil, fmt.Errorf("%w: unknown message %q in %s", ErrConfigInvalid, key, filename)
+			}
+
+			messages[key] = text
+		}
+	}
+
+	c := make(catalog, len(messages))
+	for key, text := range messages {
+		tmpl, err := template.New(key).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: cannot parse message %q: %w", ErrConfigInvalid, key, err)
+		}
+
+		c[key] = tmpl
+	}
+
+	return c, nil
+}
+
+func readMessages(filename string) (map[string]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrIO, err)
+	}
+
+	var messages map[string]string
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, fmt.Errorf("%w: cannot parse messages file %s: %w", ErrConfigInvalid, filename, err)
+	}
+
+	return messages, nil
+}
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+func (c catalog) render(key string, args messageArgs) (string, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer bufferPool.Put(buf)
+
+	buf.Reset()
+	if err := c[key].Execute(buf, args); err != nil {
+		return "", fmt.Errorf("%w: cannot render message %q: %w", ErrConfigInvalid, key, err)
+	}
+
+	return buf.String(), nil
+}
+
+// fixMessage renders the message of f, or returns it as is for the fixes of registered rules.
+func (c catalog) fixMessage(f fix, args messageArgs) (string, error) {
+	if f.message == "" {
+		return f.text, nil
+	}
+
+	return c.render(f.message, args)
+}
+
+// message renders the message of iss, followed by its layout and its preview if it has them.
+func (c catalog) message(iss issue) (string, error) {
+	msg, err := c.render(iss.code, iss.args)
+	if err != nil {
+		return "", err
+	}
+
+	if iss.args.Layout != "" {
+		layout, err := c.render(msgLayout, iss.args)
+		if err != nil {
+			return "", err
+		}
+
+		msg += "\n" + layout
+	}
+
+	if iss.args.Preview == "" {
+		return msg, nil
+	}
+
+	preview, err := c.render(msgPreview, iss.args)
+	if err != nil {
+		return "", err
+	}
+
+	return msg + "\n" + preview, nil
+}
@@ -0,0 +1,181 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// informationalRules are the rules that report about the report itself rather than about the imports.
+var informationalRules = map[string]bool{
+	codeIssueLimit:       true,
+	codeGlobalIssueLimit: true,
+}
+
+// filterIssues drops the issues of the rules disabled with -disable and, if requested, collapses issues with
+// identical messages.
+func (c *Checker) filterIssues(issues []issue) []issue {
+	seen := make(map[messageKey]bool)
+
+	var filtered []issue
+	for _, iss := range issues {
+		if c.isDisabled(iss.code) {
+			continue
+		}
+
+		key := messageKey{code: iss.code, args: iss.args}
+		if c.cfg.CollapseIdentical && seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		filtered = append(filtered, iss)
+	}
+
+	return filtered
+}
+
+type messageKey struct {
+	code string
+	args messageArgs
+}
+
+type issueKey struct {
+	pos int
+	messageKey
+}
+
+func dedupeIssues(issues []issue) []issue {
+	seen := make(map[issueKey]bool, len(issues))
+
+	var deduped []issue
+	for _, iss := range issues {
+		key := issueKey{pos: iss.pos, messageKey: messageKey{code: iss.code, args: iss.args}}
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		deduped = append(deduped, iss)
+	}
+
+	return deduped
+}
+
+// limitIssues keeps the first MaxIssuesPerFile issues and replaces the rest with a single summary issue positioned at
+// the first one left out.
+func (c *Checker) limitIssues(issues []issue) []issue {
+	limit := c.cfg.MaxIssuesPerFile
+	if limit <= 0 || len(issues) <= limit || c.isDisabled(codeIssueLimit) {
+		return issues
+	}
+
+	rest := issues[limit:]
+
+	return append(issues[:limit:limit], issue{
+		pos:  rest[0].pos,
+		end:  rest[0].end,
+		code: codeIssueLimit,
+		args: messageArgs{Count: len(rest)},
+	})
+}
+
+// globalLimiter enforces -max-issues across all the packages analyzed by one analyzer.
+type globalLimiter struct {
+	max      *int
+	mu       sync.Mutex
+	reported int
+	noted    bool
+}
+
+// limit returns the issues that still fit into the global limit, replacing the first one that does not with a note
+// about the limit, which is reported only once.
+func (l *globalLimiter) limit(c *Checker, issues []issue) []issue {
+	maxIssues := *l.max
+	if maxIssues <= 0 {
+		return issues
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.reported+len(issues) <= maxIssues {
+		l.reported += len(issues)
+		return issues
+	}
+
+	kept := issues[: maxIssues-l.reported : maxIssues-l.reported]
+	l.reported = maxIssues
+
+	if l.noted || c.isDisabled(codeGlobalIssueLimit) {
+		return kept
+	}
+
+	l.noted = true
+
+	rest := issues[len(kept)]
+
+	return append(kept, issue{
+		pos:  rest.pos,
+		end:  rest.end,
+		code: codeGlobalIssueLimit,
+		args: messageArgs{Count: maxIssues},
+	})
+}
+
+func (c *Checker) isDisabled(code string) bool {
+	for _, disabled := range strings.Split(c.cfg.Disable, ",") {
+		disabled = strings.TrimSpace(disabled)
+		if disabled == code || disabled == "info" && informationalRules[code] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isExcluded reports whether filename matches one of the -exclude patterns, either as a whole or from any of its
+// elements on, so that vendor/** matches the files below every vendor directory.
+func (c *Checker) isExcluded(filename string) bool {
+	name := filepath.ToSlash(filename)
+	for _, e := range c.excludes {
+		for rest := name; ; {
+			if e.matches(rest) {
+				return true
+			}
+
+			_, after, ok := strings.Cut(rest, "/")
+			if !ok {
+				break
+			}
+
+			rest = after
+		}
+	}
+
+	return false
+}
+
+// listValue is a flag.Value appending the values it is set to, separated by semicolons, so its flag can be repeated.
+// An empty value clears the list.
+type listValue struct {
+	list *string
+}
+
+func (v listValue) String() string {
+	if v.list == nil {
+		return ""
+	}
+
+	return *v.list
+}
+
+func (v listValue) Set(s string) error {
+	if s == "" || *v.list == "" {
+		*v.list = s
+	} else {
+		*v.list += ";" + s
+	}
+
+	return nil
+}
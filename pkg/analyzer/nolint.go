@@ -0,0 +1,109 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// linterName is the name of the linter in nolint directives.
+const linterName = "goimportgroups"
+
+// isNolint reports whether the comment is a nolint directive covering the linter, like //nolint,
+// //nolint:goimportgroups or //nolint:lll,goimportgroups // explanation.
+func isNolint(comment *ast.Comment) bool {
+	text, ok := strings.CutPrefix(comment.Text, "//nolint")
+	if !ok {
+		return false
+	}
+
+	text, _, _ = strings.Cut(text, "//")
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return true
+	}
+
+	linters, ok := strings.CutPrefix(text, ":")
+	if !ok {
+		return false
+	}
+
+	for _, linter := range strings.Split(linters, ",") {
+		if linter = strings.TrimSpace(linter); linter == linterName || linter == "all" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasNolint(group *ast.CommentGroup) bool {
+	if group == nil {
+		return false
+	}
+
+	for _, comment := range group.List {
+		if isNolint(comment) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isNolintFile reports whether a nolint directive precedes the package clause of the file, or follows it on the same
+// line, suppressing the issues of the whole file.
+func isNolintFile(tokFile *token.File, fileNode *ast.File) bool {
+	pkgLine := tokFile.Line(fileNode.Package)
+	for _, group := range fileNode.Comments {
+		if tokFile.Line(group.Pos()) > pkgLine {
+			break
+		}
+
+		if hasNolint(group) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dropNolint drops the issues suppressed by nolint directives: in the doc comment of their import declaration, or on
+// the line they start at, like the line of the import spec or of the opening parenthesis.
+func dropNolint(tokFile *token.File, fileNode *ast.File, issues []issue) []issue {
+	type span struct{ pos, end int }
+
+	var spans []span
+	for _, decl := range getImportDecls(fileNode) {
+		if hasNolint(decl.Doc) {
+			spans = append(spans, span{pos: tokFile.Offset(decl.Pos()), end: tokFile.Offset(decl.End())})
+		}
+	}
+
+	lines := make(map[int]bool)
+	for _, group := range fileNode.Comments {
+		for _, comment := range group.List {
+			if isNolint(comment) {
+				lines[tokFile.Line(comment.Pos())] = true
+			}
+		}
+	}
+
+	if len(spans) == 0 && len(lines) == 0 {
+		return issues
+	}
+
+	var kept []issue
+	for _, iss := range issues {
+		suppressed := lines[tokFile.Line(filePos(tokFile, iss.pos))]
+		for _, s := range spans {
+			suppressed = suppressed || iss.pos >= s.pos && iss.pos <= s.end
+		}
+
+		if !suppressed {
+			kept = append(kept, iss)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var (
+	policiesMu sync.RWMutex
+	policies   = make(map[string]func(cfg *Config))
+)
+
+// RegisterPolicy makes a policy pack, a named set of conventions, available to the Policies of a Config. apply sets the
+// conventions of the pack on the Config it is given. It is meant to be called from the init function of a wrapper
+// distributing compiled-in conventions, and panics if name is empty or already registered.
+func RegisterPolicy(name string, apply func(cfg *Config)) {
+	policiesMu.Lock()
+	defer policiesMu.Unlock()
+
+	if name == "" || strings.Contains(name, ",") {
+		panic(fmt.Sprintf("goimportgroups: invalid policy name %q", name))
+	}
+
+	if _, ok := policies[name]; ok {
+		panic(fmt.Sprintf("goimportgroups: policy %q registered twice", name))
+	}
+
+	policies[name] = apply
+}
+
+// applyPolicies returns cfg with the policies it enables applied in order.
+func applyPolicies(cfg Config) (Config, error) {
+	if cfg.Policies == "" {
+		return cfg, nil
+	}
+
+	policiesMu.RLock()
+	defer policiesMu.RUnlock()
+
+	for _, name := range strings.Split(cfg.Policies, ",") {
+		apply, ok := policies[strings.TrimSpace(name)]
+		if !ok {
+			return Config{}, fmt.Errorf("%w: unknown policy %q", ErrConfigInvalid, name)
+		}
+
+		apply(&cfg)
+	}
+
+	return cfg, nil
+}
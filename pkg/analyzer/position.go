@@ -0,0 +1,72 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// findPositionIssue reports the first import declaration if anything but a single blank line and its doc comment
+// separate it from the package clause. The suggested fix removes the blank lines in between, or moves the declaration
+// up if there is more than that.
+func findPositionIssue(tokFile *token.File, src []byte, fileNode *ast.File, decl *ast.GenDecl) []issue {
+	pkgLine := tokFile.Line(fileNode.Name.Pos())
+
+	start := decl.Pos()
+	if decl.Doc != nil {
+		start = decl.Doc.Pos()
+	}
+
+	startLine, endLine := tokFile.Line(start), tokFile.Line(decl.End())
+	if startLine <= pkgLine+2 || tokFile.Offset(tokFile.LineStart(startLine)) != tokFile.Offset(start) {
+		return nil
+	}
+
+	iss := newIssue(tokFile, decl, codeImportPosition, messageArgs{Line: pkgLine})
+
+	lineStart := func(line int) int {
+		if line > tokFile.LineCount() {
+			return tokFile.Size()
+		}
+
+		return tokFile.Offset(tokFile.LineStart(line))
+	}
+
+	isBlank := func(line int) bool {
+		return strings.TrimSpace(string(src[lineStart(line):lineStart(line+1)])) == ""
+	}
+
+	gapIsBlank := true
+	for line := pkgLine + 1; line < startLine; line++ {
+		gapIsBlank = gapIsBlank && isBlank(line)
+	}
+
+	if gapIsBlank {
+		iss.fixes = []fix{{
+			message: msgFixImportPosition,
+			edits:   []edit{{pos: lineStart(pkgLine + 2), end: lineStart(startLine)}},
+		}}
+
+		return []issue{iss}
+	}
+
+	declText := string(src[lineStart(startLine):lineStart(endLine+1)])
+	if !strings.HasSuffix(declText, "\n") {
+		declText += "\n"
+	}
+
+	insert := edit{pos: lineStart(pkgLine + 1), newText: "\n" + declText + "\n"}
+	if isBlank(pkgLine + 1) {
+		insert = edit{pos: lineStart(pkgLine + 2), newText: declText + "\n"}
+	}
+	insert.end = insert.pos
+
+	remove := edit{pos: lineStart(startLine), end: lineStart(endLine + 1)}
+	if isBlank(startLine - 1) {
+		remove.pos = lineStart(startLine - 1)
+	}
+
+	iss.fixes = []fix{{message: msgFixImportPosition, edits: []edit{insert, remove}}}
+
+	return []issue{iss}
+}
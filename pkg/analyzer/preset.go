@@ -0,0 +1,85 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// orgPlaceholder stands in the groups of presets for the pattern of the organization of the local module.
+const orgPlaceholder = "{org}"
+
+// preset is a layout of groups selected by name with the Preset of a Config.
+type preset struct {
+	groups string
+	names  string
+}
+
+var presets = map[string]preset{
+	"std-thirdparty": {
+		groups: "std;.* && !std",
+		names:  "std;third-party",
+	},
+	"std-thirdparty-local": {
+		groups: "std;.* && !localmodule;localmodule",
+		names:  "std;third-party;local",
+	},
+	"std-thirdparty-org-local": {
+		groups: "std;.* && !(" + orgPlaceholder + " || localmodule);" + orgPlaceholder + " && !localmodule;localmodule",
+		names:  "std;third-party;org;local",
+	},
+}
+
+// presetNames returns the names of the presets, sorted.
+func presetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// applyPreset returns cfg with the groups and the group names of its preset, if any, in place of its own.
+func applyPreset(cfg Config) (Config, error) {
+	if cfg.Preset == "" {
+		return cfg, nil
+	}
+
+	p, ok := presets[cfg.Preset]
+	if !ok {
+		return Config{}, fmt.Errorf("%w: unknown preset %q, expected one of %s", ErrConfigInvalid, cfg.Preset,
+			strings.Join(presetNames(), ", "))
+	}
+
+	cfg.Groups, cfg.GroupNames = p.groups, p.names
+	if strings.Contains(p.groups, orgPlaceholder) {
+		org, err := orgPattern(cfg.LocalModule)
+		if err != nil {
+			return Config{}, fmt.Errorf("%w (preset %s)", err, cfg.Preset)
+		}
+
+		cfg.Groups = strings.ReplaceAll(p.groups, orgPlaceholder, org)
+	}
+
+	return cfg, nil
+}
+
+// orgPattern returns the pattern matching the packages of the organization of the module path, the path without
+// its last element and major version suffix, like github.com/org for github.com/org/app/v2.
+func orgPattern(modulePath string) (string, error) {
+	prefix, _, _ := module.SplitPathVersion(modulePath)
+
+	i := strings.LastIndex(prefix, "/")
+	if i <= 0 {
+		return "", fmt.Errorf("%w: the organization needs a local module path of several elements, got %q",
+			ErrConfigInvalid, modulePath)
+	}
+
+	return regexp.QuoteMeta(prefix[:i]) + "/.*", nil
+}
@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// firstRegroupFix leaves the regroup fix to the first of issues holding it, the fix rewriting the whole declaration
+// once for all the issues it resolves.
+func firstRegroupFix(issues []issue) []issue {
+	held := false
+	for i := range issues {
+		if len(issues[i].fixes) == 0 || issues[i].fixes[0].message != msgFixRegroup {
+			continue
+		}
+
+		if held {
+			issues[i].fixes = nil
+		}
+
+		held = true
+	}
+
+	return issues
+}
+
+// regroupFix returns the fix replacing decl with the import declaration its blocks are expected to form. There is no
+// fix if decl is as expected already, or if it holds comments that are neither the doc nor the trailing comment of an
+// import, since they would have no place in the rendered declaration, or if it imports "C", whose preamble is left
+// untouched. Redundant aliases are kept, the redundant-alias rule removes the ones type information proves redundant.
+func regroupFix(
+	tokFile *token.File,
+	src []byte,
+	fileNode *ast.File,
+	decl *ast.GenDecl,
+	blocks [][]importSpec,
+	groupNames []string,
+	relaxed bool,
+	style Style,
+) (fix, bool) {
+	if !decl.Lparen.IsValid() || hasFloatingComments(fileNode, decl) || importsCgo(decl) {
+		return fix{}, false
+	}
+
+	style.RemoveRedundantAliases = false
+
+	lines, err := renderBlockLines(exportGroups(expectedBlocks(blocks, len(groupNames), relaxed), groupNames), style)
+	if err != nil {
+		return fix{}, false
+	}
+
+	pos, end := tokFile.Offset(decl.Pos()), tokFile.Offset(decl.End())
+
+	expected := strings.Join(lines, "\n")
+	if expected == string(src[pos:end]) {
+		return fix{}, false
+	}
+
+	return fix{message: msgFixRegroup, edits: []edit{{pos: pos, end: end, newText: expected}}}, true
+}
+
+// hasFloatingComments reports whether the parentheses of decl hold comments not attached to any of its specs.
+func hasFloatingComments(fileNode *ast.File, decl *ast.GenDecl) bool {
+	attached := make(map[*ast.CommentGroup]bool)
+	for _, s := range decl.Specs {
+		spec := s.(*ast.ImportSpec)
+		attached[spec.Doc] = true
+		attached[spec.Comment] = true
+	}
+
+	for _, group := range fileNode.Comments {
+		if group.Pos() > decl.Lparen && group.End() < decl.Rparen && !attached[group] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,422 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// expectedBlocks regroups the imports of blocks by the group they belong to, in the configured group order, or in the
+// order the groups first appear in if relaxed, keeping the original order within a group. Imports that match no group
+// are put into a last block of their own.
+func expectedBlocks(blocks [][]importSpec, groupCount int, relaxed bool) [][]importSpec {
+	grouped := make([][]importSpec, groupCount+1)
+	var order []int
+	for _, block := range blocks {
+		for _, spec := range block {
+			i := spec.group
+			if i < 0 {
+				i = groupCount
+			}
+
+			if len(grouped[i]) == 0 && i < groupCount {
+				order = append(order, i)
+			}
+
+			grouped[i] = append(grouped[i], spec)
+		}
+	}
+
+	if !relaxed {
+		order = order[:0]
+		for i := 0; i < groupCount; i++ {
+			order = append(order, i)
+		}
+	}
+
+	var expected [][]importSpec
+	for _, i := range append(order, groupCount) {
+		if len(grouped[i]) > 0 {
+			expected = append(expected, grouped[i])
+		}
+	}
+
+	return expected
+}
+
+// Group is a group of imports, rendered as one block of the import declaration.
+type Group struct {
+	// Name is rendered as a comment heading the block when Style.HeaderComments is set.
+	Name    string
+	Imports []Import
+}
+
+// Import is a single import of a Group.
+type Import struct {
+	// Name is the alias of the import, if any, including "_" and ".".
+	Name string
+	Path string
+	// Literal is the path as written in the source, quotes included. It is rendered instead of the quoted Path if set,
+	// unless Style.NormalizeQuotes is set.
+	Literal string
+	// Doc holds the comment lines preceding the import.
+	Doc []string
+	// Comment is the comment following the import on the same line.
+	Comment string
+}
+
+// SortOrder is the order of the imports within a group.
+type SortOrder string
+
+const (
+	// SortNone keeps the imports in their given order.
+	SortNone SortOrder = ""
+	// SortPath sorts imports by path.
+	SortPath SortOrder = "path"
+	// SortCaseInsensitive sorts imports by path, ignoring case.
+	SortCaseInsensitive SortOrder = "case-insensitive"
+	// SortDomain sorts imports by their first path element, then by the rest of the path.
+	SortDomain SortOrder = "domain"
+	// SortStdFirst puts imports looking like standard library ones, with no dot in their first path element, first
+	// and sorts both parts by path.
+	SortStdFirst SortOrder = "std-first"
+)
+
+// ParseSortOrder returns the SortOrder named s, "none" standing for SortNone.
+func ParseSortOrder(s string) (SortOrder, error) {
+	switch order := SortOrder(s); order {
+	case SortNone, SortPath, SortCaseInsensitive, SortDomain, SortStdFirst:
+		return order, nil
+	case "none":
+		return SortNone, nil
+	default:
+		return "", fmt.Errorf("%w: unknown sort order %q", ErrConfigInvalid, s)
+	}
+}
+
+// Style controls how RenderBlock lays out import declarations.
+type Style struct {
+	// Sort is the order of the imports within each group.
+	Sort SortOrder
+	// SinkBlankDot moves blank (_) and dot (.) imports to the end of their group, after sorting.
+	SinkBlankDot bool
+	// HeaderComments heads each block with a comment holding the name of its group.
+	HeaderComments bool
+	// AlignAliases pads the aliases of each block to the same width so the paths line up in a column. Note that gofmt
+	// does not preserve this alignment.
+	AlignAliases bool
+	// NormalizeQuotes always renders paths as double-quoted strings, ignoring Import.Literal.
+	NormalizeQuotes bool
+	// RemoveRedundantAliases drops aliases equal to the name the package is known by anyway, see DefaultPackageName.
+	RemoveRedundantAliases bool
+}
+
+// RenderBlock renders groups as a factored import declaration, one block per non-empty group in the given order,
+// separated by blank lines. Comments not starting with // or /* are rendered as line comments.
+func RenderBlock(groups []Group, style Style) ([]byte, error) {
+	lines, err := renderBlockLines(groups, style)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(strings.Join(lines, "\n") + "\n"), nil
+}
+
+func renderBlockLines(groups []Group, style Style) ([]string, error) {
+	// the header comments rendered before are doc comments of the imports now, drop them not to repeat them
+	headers := make(map[string]bool)
+	if style.HeaderComments {
+		for _, group := range groups {
+			if group.Name != "" {
+				headers[renderComment(group.Name)] = true
+			}
+		}
+	}
+
+	// comments holds the trailing comment of each line, aligned in a column over runs of commented lines like gofmt
+	lines, comments := []string{"import ("}, []string{""}
+	add := func(line, comment string) {
+		lines, comments = append(lines, line), append(comments, comment)
+	}
+
+	for _, group := range groups {
+		if len(group.Imports) == 0 {
+			continue
+		}
+
+		if len(lines) > 1 {
+			add("", "")
+		}
+
+		if style.HeaderComments && group.Name != "" {
+			add("\t"+renderComment(group.Name), "")
+		}
+
+		imports := sortImports(group.Imports, style)
+		if style.RemoveRedundantAliases {
+			for i := range imports {
+				if imports[i].Name != "" && imports[i].Name == DefaultPackageName(imports[i].Path) {
+					imports[i].Name = ""
+				}
+			}
+		}
+
+		width := 0
+		if style.AlignAliases {
+			for _, imp := range imports {
+				if len(imp.Name) > width {
+					width = len(imp.Name)
+				}
+			}
+		}
+
+		for _, imp := range imports {
+			if imp.Path == "" {
+				return nil, fmt.Errorf("empty import path in group %q", group.Name)
+			}
+
+			if imp.Name != "" && imp.Name != "_" && imp.Name != "." && !token.IsIdentifier(imp.Name) {
+				return nil, fmt.Errorf("invalid name %q for import %q", imp.Name, imp.Path)
+			}
+
+			for _, doc := range imp.Doc {
+				if !headers[renderComment(doc)] {
+					add("\t"+renderComment(doc), "")
+				}
+			}
+
+			line := strconv.Quote(imp.Path)
+			if imp.Literal != "" && !style.NormalizeQuotes {
+				line = imp.Literal
+			}
+
+			if imp.Name != "" || width > 0 {
+				line = fmt.Sprintf("%-*s %s", width, imp.Name, line)
+			}
+
+			comment := ""
+			if imp.Comment != "" {
+				comment = renderComment(imp.Comment)
+			}
+
+			add("\t"+strings.TrimLeft(line, " "), comment)
+		}
+	}
+
+	add(")", "")
+	alignComments(lines, comments)
+
+	return lines, nil
+}
+
+// alignComments appends comments to lines, padding the lines of each run of consecutive commented lines to the same
+// width the way gofmt does.
+func alignComments(lines, comments []string) {
+	for start := 0; start < len(lines); {
+		if comments[start] == "" {
+			start++
+			continue
+		}
+
+		end, width := start, 0
+		for ; end < len(lines) && comments[end] != ""; end++ {
+			if n := utf8.RuneCountInString(lines[end]); n > width {
+				width = n
+			}
+		}
+
+		for i := start; i < end; i++ {
+			lines[i] += strings.Repeat(" ", width-utf8.RuneCountInString(lines[i])+1) + comments[i]
+		}
+
+		start = end
+	}
+}
+
+// sortImports returns a sorted copy of imports.
+func sortImports(imports []Import, style Style) []Import {
+	sorted := append([]Import(nil), imports...)
+
+	if less := importLess(style.Sort); less != nil {
+		sort.SliceStable(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+	}
+
+	if style.SinkBlankDot {
+		sort.SliceStable(sorted, func(i, j int) bool { return !isBlankOrDot(sorted[i]) && isBlankOrDot(sorted[j]) })
+	}
+
+	return sorted
+}
+
+// importLess returns the comparison of the imports sorted in order, nil for SortNone.
+func importLess(order SortOrder) func(a, b Import) bool {
+	switch order {
+	case SortPath:
+		return func(a, b Import) bool { return a.Path < b.Path }
+	case SortCaseInsensitive:
+		return func(a, b Import) bool { return strings.ToLower(a.Path) < strings.ToLower(b.Path) }
+	case SortDomain:
+		return func(a, b Import) bool {
+			aDomain, aRest, _ := strings.Cut(a.Path, "/")
+			bDomain, bRest, _ := strings.Cut(b.Path, "/")
+			if aDomain != bDomain {
+				return aDomain < bDomain
+			}
+
+			return aRest < bRest
+		}
+	case SortStdFirst:
+		return func(a, b Import) bool {
+			if aStd, bStd := looksStd(a.Path), looksStd(b.Path); aStd != bStd {
+				return aStd
+			}
+
+			return a.Path < b.Path
+		}
+	default:
+		return nil
+	}
+}
+
+// sortedBefore reports whether a goes before b in imports sorted by style.
+func sortedBefore(a, b Import, style Style) bool {
+	if style.SinkBlankDot && isBlankOrDot(a) != isBlankOrDot(b) {
+		return isBlankOrDot(b)
+	}
+
+	less := importLess(style.Sort)
+	return less != nil && less(a, b)
+}
+
+func looksStd(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+func isBlankOrDot(imp Import) bool {
+	return imp.Name == "_" || imp.Name == "."
+}
+
+// DefaultPackageName guesses the name of the package imported by path from its last element, skipping major version
+// elements like v2 and trimming gopkg.in style .vN suffixes. It returns "" if that element is not a valid identifier,
+// e.g. because it contains dashes, in which case the actual name cannot be guessed.
+func DefaultPackageName(path string) string {
+	elems := strings.Split(path, "/")
+
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && isMajorVersion(name) {
+		name = elems[len(elems)-2]
+	}
+
+	if i := strings.LastIndex(name, "."); i >= 0 && isMajorVersion(name[i+1:]) {
+		name = name[:i]
+	}
+
+	if !token.IsIdentifier(name) {
+		return ""
+	}
+
+	return name
+}
+
+func isMajorVersion(elem string) bool {
+	if len(elem) < 2 || elem[0] != 'v' {
+		return false
+	}
+
+	for _, r := range elem[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func renderComment(text string) string {
+	if strings.HasPrefix(text, "//") || strings.HasPrefix(text, "/*") {
+		return text
+	}
+
+	return "// " + text
+}
+
+// exportGroups converts blocks into groups named after the names of the groups they belong to.
+func exportGroups(blocks [][]importSpec, groupNames []string) []Group {
+	groups := make([]Group, len(blocks))
+	for i, block := range blocks {
+		if block[0].group >= 0 {
+			groups[i].Name = groupNames[block[0].group]
+		}
+
+		for _, spec := range block {
+			groups[i].Imports = append(groups[i].Imports, exportImport(spec))
+		}
+	}
+
+	return groups
+}
+
+func exportImport(spec importSpec) Import {
+	imp := Import{Path: spec.path, Literal: spec.node.Path.Value}
+	if spec.node.Name != nil {
+		imp.Name = spec.node.Name.Name
+	}
+
+	if spec.node.Doc != nil {
+		for _, c := range spec.node.Doc.List {
+			imp.Doc = append(imp.Doc, c.Text)
+		}
+	}
+
+	if spec.node.Comment != nil {
+		var texts []string
+		for _, c := range spec.node.Comment.List {
+			texts = append(texts, c.Text)
+		}
+
+		imp.Comment = strings.Join(texts, " ")
+	}
+
+	return imp
+}
+
+// renderPreview renders up to maxLines lines of the expected import declaration, starting at the first line that
+// differs from decl as found in src, and returns them along with the line number they start at.
+func renderPreview(
+	tokFile *token.File, src []byte, decl *ast.GenDecl, blocks [][]importSpec, groupNames []string, relaxed bool,
+	style Style, maxLines int,
+) (int, string) {
+	start := tokFile.Offset(tokFile.LineStart(tokFile.Line(decl.Pos())))
+	end := tokFile.Offset(decl.End())
+	actual := strings.Split(string(src[start:end]), "\n")
+
+	expected, err := renderBlockLines(exportGroups(expectedBlocks(blocks, len(groupNames), relaxed), groupNames), style)
+	if err != nil {
+		return 0, ""
+	}
+
+	first := 0
+	for first < len(expected) && first < len(actual) {
+		if strings.TrimSpace(expected[first]) != strings.TrimSpace(actual[first]) {
+			break
+		}
+
+		first++
+	}
+
+	if first == len(expected) {
+		return 0, ""
+	}
+
+	last := first + maxLines
+	if last > len(expected) {
+		last = len(expected)
+	}
+
+	return tokFile.Line(decl.Pos()) + first, strings.Join(expected[first:last], "\n")
+}
@@ -0,0 +1,125 @@
+package analyzer_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+func TestRenderBlock(t *testing.T) {
+	groups := []analyzer.Group{
+		{Name: "std", Imports: []analyzer.Import{{Path: "fmt"}, {Path: "os", Comment: "for Exit"}}},
+		{Name: "empty"},
+		{Name: "third-party", Imports: []analyzer.Import{
+			{Name: "yaml", Path: "gopkg.in/yaml.v3", Doc: []string{"// config files"}},
+			{Name: "_", Path: "github.com/lib/pq"},
+			{Path: "github.com/acme/x"},
+		}},
+	}
+
+	out, err := analyzer.RenderBlock(groups, analyzer.Style{HeaderComments: true, AlignAliases: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `import (
+	// std
+	"fmt"
+	"os" // for Exit
+
+	// third-party
+	// config files
+	yaml "gopkg.in/yaml.v3"
+	_    "github.com/lib/pq"
+	"github.com/acme/x"
+)
+`
+	if string(out) != expected {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	_, err = analyzer.RenderBlock([]analyzer.Group{{Imports: []analyzer.Import{{Name: "a-b", Path: "x"}}}}, analyzer.Style{})
+	if err == nil {
+		t.Error("expected an error for an invalid import name")
+	}
+}
+
+func TestRenderBlockSort(t *testing.T) {
+	imports := []analyzer.Import{
+		{Name: "_", Path: "embed"},
+		{Path: "github.com/b/x"},
+		{Path: "Github.com/a/x"},
+		{Path: "strings"},
+		{Path: "gitlab.com/a"},
+		{Path: "github.com/a/y"},
+	}
+
+	for _, tc := range []struct {
+		style    analyzer.Style
+		expected []string
+	}{
+		{
+			style:    analyzer.Style{Sort: analyzer.SortPath},
+			expected: []string{"Github.com/a/x", "embed", "github.com/a/y", "github.com/b/x", "gitlab.com/a", "strings"},
+		},
+		{
+			style:    analyzer.Style{Sort: analyzer.SortCaseInsensitive},
+			expected: []string{"embed", "Github.com/a/x", "github.com/a/y", "github.com/b/x", "gitlab.com/a", "strings"},
+		},
+		{
+			style:    analyzer.Style{Sort: analyzer.SortStdFirst, SinkBlankDot: true},
+			expected: []string{"strings", "Github.com/a/x", "github.com/a/y", "github.com/b/x", "gitlab.com/a", "embed"},
+		},
+		{
+			style:    analyzer.Style{SinkBlankDot: true},
+			expected: []string{"github.com/b/x", "Github.com/a/x", "strings", "gitlab.com/a", "github.com/a/y", "embed"},
+		},
+	} {
+		out, err := analyzer.RenderBlock([]analyzer.Group{{Imports: imports}}, tc.style)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+		lines = lines[1 : len(lines)-1]
+
+		for i, line := range lines {
+			line = strings.TrimPrefix(strings.TrimSpace(line), "_ ")
+			if line != strconv.Quote(tc.expected[i]) {
+				t.Errorf("sort %q: expected %q at %d, got %s", tc.style.Sort, tc.expected[i], i, line)
+			}
+		}
+	}
+}
+
+func TestRenderBlockAliases(t *testing.T) {
+	groups := []analyzer.Group{{Imports: []analyzer.Import{
+		{Name: "fmt", Path: "fmt", Literal: "`fmt`"},
+		{Name: "yaml", Path: "gopkg.in/yaml.v3"},
+		{Name: "mux", Path: "github.com/gorilla/mux/v2"},
+		{Name: "longname", Path: "github.com/acme/go-lib"},
+	}}}
+
+	out, err := analyzer.RenderBlock(groups, analyzer.Style{AlignAliases: true, RemoveRedundantAliases: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "import (\n\t`fmt`\n\t\"gopkg.in/yaml.v3\"\n\t\"github.com/gorilla/mux/v2\"\n\tlongname \"github.com/acme/go-lib\"\n)\n"
+	if string(out) != expected {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	out, err = analyzer.RenderBlock(groups, analyzer.Style{AlignAliases: true, NormalizeQuotes: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = "import (\n\tfmt      \"fmt\"\n\tyaml     \"gopkg.in/yaml.v3\"\n\tmux      \"github.com/gorilla/mux/v2\"\n" +
+		"\tlongname \"github.com/acme/go-lib\"\n)\n"
+	if string(out) != expected {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
@@ -0,0 +1,149 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"sync"
+)
+
+// Rule is a custom import rule compiled into a wrapper, reusing the parsing, fixing, reporting and noise controls of
+// the checks. Its name is its rule code, used to disable it and as the key of its message, which is the message of
+// the finding unless overridden.
+type Rule interface {
+	Name() string
+	// Inspect returns the findings of the rule in a block of imports of a file.
+	Inspect(block ImportBlock, file FileInfo) []Finding
+}
+
+// ImportBlock is a block of import specs, separated from the others by blank lines, along with the groups they match.
+type ImportBlock struct {
+	Specs []*ast.ImportSpec
+	// Paths holds the unquoted import path of each spec.
+	Paths []string
+	// Groups holds the index of the first group pattern matching each spec, or -1 if none does.
+	Groups []int
+}
+
+// FileInfo describes the file an ImportBlock belongs to.
+type FileInfo struct {
+	Name string
+	// Package is the name in the package clause of the file.
+	Package string
+	// Fset holds the positions of the specs, to convert them to the positions of findings and their fixes.
+	Fset *token.FileSet
+}
+
+// Finding is a violation found by a Rule, reported as an Issue with the name of the rule as its code. Only the
+// offsets of the positions of findings and fixes are used.
+type Finding struct {
+	Pos     token.Position
+	End     token.Position
+	Message string
+	Fixes   []Fix
+}
+
+var (
+	rulesMu sync.RWMutex
+	rules   = make(map[string]Rule)
+)
+
+// RegisterRule adds r to the rules of every Checker created afterwards. It is meant to be called from the init
+// function of a wrapper, and panics if the name of r is empty or is already the code of a rule.
+func RegisterRule(r Rule) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+
+	name := r.Name()
+	if name == "" {
+		panic("goimportgroups: rule without a name")
+	}
+
+	if _, ok := defaultMessages[name]; ok {
+		panic(fmt.Sprintf("goimportgroups: rule %q is built in", name))
+	}
+
+	if _, ok := rules[name]; ok {
+		panic(fmt.Sprintf("goimportgroups: rule %q registered twice", name))
+	}
+
+	rules[name] = r
+}
+
+// registeredRules returns the registered rules sorted by name.
+func registeredRules() []Rule {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+
+	sorted := make([]Rule, 0, len(rules))
+	for _, r := range rules {
+		sorted = append(sorted, r)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name() < sorted[j].Name()
+	})
+
+	return sorted
+}
+
+// findRuleIssues runs the rules of the checker over every block of imports of the file.
+func (c *Checker) findRuleIssues(
+	fset *token.FileSet, tokFile *token.File, fileNode *ast.File, decls []*ast.GenDecl,
+) ([]issue, error) {
+	if len(c.rules) == 0 {
+		return nil, nil
+	}
+
+	info := FileInfo{Name: tokFile.Name(), Package: fileNode.Name.Name, Fset: fset}
+
+	var issues []issue
+	for _, decl := range decls {
+		for _, specs := range getImportBlocks(tokFile, decl) {
+			block := ImportBlock{Specs: make([]*ast.ImportSpec, len(specs))}
+			for i, spec := range specs {
+				group, err := c.matcher.groupOf(spec.path, c.patterns)
+				if err != nil {
+					return nil, err
+				}
+
+				block.Specs[i] = spec.node
+				block.Paths = append(block.Paths, spec.path)
+				block.Groups = append(block.Groups, group)
+			}
+
+			for _, r := range c.rules {
+				for _, finding := range r.Inspect(block, info) {
+					issues = append(issues, findingIssue(r.Name(), finding))
+				}
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+func findingIssue(code string, finding Finding) issue {
+	iss := issue{
+		pos:  finding.Pos.Offset,
+		end:  finding.End.Offset,
+		code: code,
+		args: messageArgs{Message: finding.Message},
+	}
+
+	for _, f := range finding.Fixes {
+		converted := fix{text: f.Message}
+		for _, e := range f.Edits {
+			converted.edits = append(converted.edits, edit{
+				pos:     e.Pos.Offset,
+				end:     e.End.Offset,
+				newText: string(e.NewText),
+			})
+		}
+
+		iss.fixes = append(iss.fixes, converted)
+	}
+
+	return iss
+}
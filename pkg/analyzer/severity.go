@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The severities of the issues. The command exits with 1 for the issues of SeverityError only.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// parseSeverity returns the severity s, SeverityError if empty.
+func parseSeverity(s string) (string, error) {
+	switch s = strings.TrimSpace(s); s {
+	case "":
+		return SeverityError, nil
+	case SeverityError, SeverityWarning:
+		return s, nil
+	default:
+		return "", fmt.Errorf("%w: unknown severity %q, expected %s or %s", ErrConfigInvalid, s, SeverityError,
+			SeverityWarning)
+	}
+}
+
+// parseSeverities returns the severities of the rules of a Config.Severities list of code=severity pairs.
+func parseSeverities(list string) (map[string]string, error) {
+	severities := make(map[string]string)
+	if list == "" {
+		return severities, nil
+	}
+
+	for _, pair := range strings.Split(list, ",") {
+		code, severity, ok := strings.Cut(pair, "=")
+		code = strings.TrimSpace(code)
+		if !ok || code == "" {
+			return nil, fmt.Errorf("%w: severity %q is no code=severity pair", ErrConfigInvalid, pair)
+		}
+
+		s, err := parseSeverity(severity)
+		if err != nil {
+			return nil, fmt.Errorf("%w (rule %s)", err, code)
+		}
+
+		severities[code] = s
+	}
+
+	return severities, nil
+}
+
+// severity returns the severity of the issues of the rule code, the one of Config.Severities or Config.Severity.
+func (c *Checker) severity(code string) string {
+	if s, ok := c.severities[code]; ok {
+		return s
+	}
+
+	return c.defaultSeverity
+}
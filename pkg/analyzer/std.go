@@ -0,0 +1,108 @@
+package analyzer
+
+import (
+	_ "embed"
+	"strconv"
+	"strings"
+)
+
+//go:generate sh -c "(echo '# The importable packages of the standard library of '$(go env GOVERSION)', generated by go generate.'; go list std | grep -v -e '^vendor/' -e '/internal' -e '^internal/') > std.txt"
+
+// stdKeyword is the group pattern matching exactly the packages of the standard library.
+const stdKeyword = "std"
+
+//go:embed std.txt
+var stdList string
+
+// stdPackages is the set of the importable packages of the standard library, the ones of stdAdded included.
+var stdPackages = parseStdList(stdList)
+
+// stdAdded maps the packages added to the standard library since go1.16 to the Go version adding them, the ones
+// newer than the toolchain generating std.txt included, for std to match the packages of the Go version of the
+// checked files only.
+var stdAdded = map[string]string{
+	"embed":            "go1.16",
+	"io/fs":            "go1.16",
+	"runtime/metrics":  "go1.16",
+	"testing/fstest":   "go1.16",
+	"debug/buildinfo":  "go1.18",
+	"net/netip":        "go1.18",
+	"go/doc/comment":   "go1.19",
+	"crypto/ecdh":      "go1.20",
+	"cmp":              "go1.21",
+	"log/slog":         "go1.21",
+	"maps":             "go1.21",
+	"slices":           "go1.21",
+	"testing/slogtest": "go1.21",
+	"go/version":       "go1.22",
+	"math/rand/v2":     "go1.22",
+	"iter":             "go1.23",
+	"structs":          "go1.23",
+	"unique":           "go1.23",
+	"crypto/fips140":   "go1.24",
+	"crypto/hkdf":      "go1.24",
+	"crypto/mlkem":     "go1.24",
+	"crypto/pbkdf2":    "go1.24",
+	"crypto/sha3":      "go1.24",
+	"weak":             "go1.24",
+	"testing/synctest": "go1.25",
+}
+
+func init() {
+	for path := range stdAdded {
+		stdPackages[path] = true
+	}
+}
+
+func parseStdList(list string) map[string]bool {
+	packages := make(map[string]bool)
+	for _, line := range strings.Split(list, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		packages[line] = true
+	}
+
+	return packages
+}
+
+// isStd reports whether importPath is a package of the standard library of goVersion, like go1.22, or of any
+// version if goVersion is empty or invalid.
+func isStd(importPath, goVersion string) bool {
+	if !stdPackages[importPath] {
+		return false
+	}
+
+	added, ok := stdAdded[importPath]
+	if !ok {
+		return true
+	}
+
+	minor, ok := goMinor(goVersion)
+	if !ok {
+		return true
+	}
+
+	addedMinor, _ := goMinor(added)
+
+	return minor >= addedMinor
+}
+
+// goMinor returns the minor version of the Go version v, like 22 for go1.22 or go1.22.3, and whether v is valid.
+func goMinor(v string) (int, bool) {
+	rest, ok := strings.CutPrefix(v, "go1.")
+	if !ok {
+		return 0, false
+	}
+
+	end := 0
+	for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+		end++
+	}
+
+	minor, err := strconv.Atoi(rest[:end])
+
+	return minor, err == nil
+}
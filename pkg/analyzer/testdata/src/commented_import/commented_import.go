@@ -0,0 +1,15 @@
+package commented_import
+
+import (
+	"fmt"
+	// "github.com/old/dep" // want `import "github.com/old/dep" is commented out`
+	"strings" // "strings" is needed by Nothing
+
+	// old "github.com/old/other" // want `import "github.com/old/other" is commented out`
+	// the imports below are kept sorted
+	"os"
+)
+
+func Nothing() {
+	fmt.Println(strings.ToUpper(os.Args[0]))
+}
@@ -0,0 +1,16 @@
+package decl_groups
+
+import (
+	"fmt"
+
+	"os"
+)
+
+import "strings"
+
+import (
+	"time" // want `import "time" belongs to group "time" \(group 2\) but appears after group 3 \("strings"\)`
+	"regexp" // want `import "regexp" belongs to group "regexp" \(group 4\) but appears in group 2 \("time"\)`
+)
+
+var _, _, _, _, _ = fmt.Println, os.Exit, time.Now, strings.Cut, regexp.Compile
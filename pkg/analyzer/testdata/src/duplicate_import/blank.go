@@ -0,0 +1,10 @@
+package duplicate_import
+
+import (
+	_ "strings"
+	stdstrings "strings" // want `import "strings" as stdstrings duplicates its import as _ at line 4`
+)
+
+func Blank() string {
+	return stdstrings.Repeat("x", 2)
+}
@@ -0,0 +1,10 @@
+package duplicate_import
+
+import (
+	"strings"
+	str "strings" // want `import "strings" as str duplicates its import as strings at line 4`
+)
+
+func Safe() string {
+	return strings.ToUpper(str.TrimSpace(" x "))
+}
@@ -0,0 +1,14 @@
+package duplicate_import
+
+import (
+	"strings"
+	s "strings" // want `import "strings" as s duplicates its import as strings at line 4`
+)
+
+func Shadowed(strings []string) string {
+	return s.Join(strings, ",")
+}
+
+func NotShadowed() string {
+	return strings.ToLower("X")
+}
@@ -0,0 +1,6 @@
+package empty_decl
+
+import () // want `import declaration without imports`
+
+func Empty() {
+}
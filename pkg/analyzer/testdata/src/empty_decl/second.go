@@ -0,0 +1,10 @@
+package empty_decl
+
+import "fmt"
+
+import ( // want `import declaration without imports`
+)
+
+func Second() {
+	fmt.Println()
+}
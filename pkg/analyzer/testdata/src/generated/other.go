@@ -0,0 +1,9 @@
+package generated
+
+import (
+	"time"
+
+	"fmt" // want `import "fmt" belongs to group "fmt:os" \(group 1\) but appears after group 2 \("time"\)`
+)
+
+var _, _ = fmt.Println, time.Now
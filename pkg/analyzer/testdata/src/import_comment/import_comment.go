@@ -0,0 +1,13 @@
+package import_comment
+
+import (
+	"fmt"
+
+	_ "bytes"   // indirect-tool
+	_ "errors"  // want `import "errors" in group 2 must have a comment matching "indirect-tool"`
+	_ "strings" //indirect-tool // want `import "strings" in group 2 must have a comment matching "indirect-tool"`
+)
+
+func Nothing() {
+	fmt.Println()
+}
@@ -0,0 +1,10 @@
+package import_position
+
+
+
+// Package fmt is needed.
+import "fmt" // want `imports should follow the package clause at line 1`
+
+func Gap() {
+	fmt.Println()
+}
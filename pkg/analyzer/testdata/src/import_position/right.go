@@ -0,0 +1,8 @@
+package import_position
+
+// Package strings is needed.
+import "strings"
+
+func Right() string {
+	return strings.ToUpper("")
+}
@@ -0,0 +1,11 @@
+package import_position
+
+// TODO: split this file.
+
+import ( // want `imports should follow the package clause at line 1`
+	"os"
+)
+
+func Stray() {
+	os.Exit(0)
+}
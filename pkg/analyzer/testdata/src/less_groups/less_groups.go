@@ -1,13 +1,13 @@
 package main
 
-import ( // want `File is not goimportgroups-ed`
+import (
 	"fmt"
 	"os"
 
 	"time"
 
 	"regexp"
-	"strings"
+	"strings" // want `import "strings" belongs to group "strings" \(group 3\) but appears in group 4 \("regexp"\)`
 )
 
 func Nothing() {
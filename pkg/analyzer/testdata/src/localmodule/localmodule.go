@@ -0,0 +1,9 @@
+package localmodule
+
+import (
+	"localmodule/sub"
+
+	"fmt" // want `import "fmt" belongs to group "std" \(group 1\) but appears after group 2 \("localmodule"\)`
+)
+
+var _ = fmt.Sprint(sub.Name)
@@ -0,0 +1,12 @@
+package merge_decls
+
+import "time"
+
+import ( // want `multiple import declarations: imports are already declared at line 3`
+	"strings"
+	"fmt" // printing
+)
+
+import "os" // want `multiple import declarations: imports are already declared at line 3`
+
+var _, _, _, _ = fmt.Println, os.Exit, time.Now, strings.Cut
@@ -0,0 +1,12 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time" // want `Import "time" gehört zu Gruppe 2 \(time\), steht aber in Gruppe 1 \(fmt:os\)`
+)
+
+func Nothing() {
+	fmt.Println(os.Getenv("test"))
+	fmt.Println(time.Now().String())
+}
@@ -0,0 +1,7 @@
+package missing_group
+
+import ( // want `imports lack the required group "core" \(group 1\)`
+	"time"
+)
+
+var _ = time.Now
@@ -0,0 +1,9 @@
+package missing_group
+
+import (
+	"fmt"
+
+	"time"
+)
+
+var _, _ = time.Now, fmt.Println
@@ -5,7 +5,7 @@ import (
 	"os"
 )
 
-import ( // want `File is not goimportgroups-ed: cannot have two import sections`
+import ( // want `multiple import declarations: imports are already declared at line 3`
 	"regexp"
 	"strings"
 	"time"
@@ -0,0 +1,11 @@
+package named_groups
+
+import (
+	// core
+	"os"
+
+	// clock
+	"time"
+)
+
+var _, _ = time.Now, os.Exit
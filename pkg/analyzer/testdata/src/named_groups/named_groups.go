@@ -0,0 +1,9 @@
+package named_groups
+
+import (
+	"time"
+
+	"fmt" // want `import "fmt" belongs to group "core" \(group 1\) but appears after group 2 \("clock"\)`
+)
+
+var _, _ = time.Now, fmt.Println
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"  // want `import "regexp" belongs to group "regexp" \(group 4\) but appears in group 1 \("fmt:os"\)`
+	"strings" // want `import "strings" belongs to group "strings" \(group 3\) but appears in group 1 \("fmt:os"\)`
+	"time"    // want `the limit of 2 issues is reached, further issues are not reported`
+)
+
+func Nothing() {
+	fmt.Println(errors.New("test"))
+	fmt.Println(strings.HasPrefix("a", "b"))
+	fmt.Println(os.Getenv("test"))
+	fmt.Println(time.Now().String())
+	fmt.Println(regexp.Regexp{})
+}
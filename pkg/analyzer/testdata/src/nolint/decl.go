@@ -0,0 +1,10 @@
+package nolint
+
+//nolint:lll,goimportgroups // intentional
+import (
+	"time"
+
+	"fmt"
+)
+
+var _, _ = time.Now, fmt.Println
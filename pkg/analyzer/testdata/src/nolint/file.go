@@ -0,0 +1,10 @@
+//nolint:goimportgroups // generated
+package nolint
+
+import (
+	"time"
+
+	"fmt"
+)
+
+var _, _ = time.Now, fmt.Println
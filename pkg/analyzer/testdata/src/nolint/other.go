@@ -0,0 +1,9 @@
+package nolint
+
+import (
+	"time" //nolint:lll
+
+	"fmt" // want `import "fmt" belongs to group "fmt:os" \(group 1\) but appears after group 2 \("time"\)`
+)
+
+var _, _ = time.Now, fmt.Println
@@ -0,0 +1,8 @@
+package nolint
+
+import (
+	"fmt"
+	"time" //nolint:goimportgroups
+)
+
+var _, _ = time.Now, fmt.Println
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"time"
+
+	"regexp"
+	"strings" // want `import "strings" belongs to group "strings" \(group 3\) but appears in group 4 \("regexp"\)\nexpected imports from line 9:\n\t"strings" //.*\n\n\t"regexp"$`
+)
+
+func Nothing() {
+	fmt.Println(strings.HasPrefix("a", "b"))
+	fmt.Println(os.Getenv("test"))
+	fmt.Println(time.Now().String())
+	fmt.Println(regexp.Regexp{})
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	fmt "fmt"                // want `import "fmt" is aliased to its package name fmt`
+	template "html/template" // want `import "html/template" is aliased to its package name template`
+	tpl "text/template"
+)
+
+func Nothing() {
+	fmt.Println(template.HTML(""), tpl.New(""))
+}
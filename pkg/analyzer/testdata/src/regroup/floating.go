@@ -0,0 +1,10 @@
+package main
+
+import (
+	"regexp"
+	"fmt" // want `import "fmt" belongs to group "fmt:os" \(group 1\) but appears in group 4 \("regexp"\)`
+	// more to come
+)
+
+var _ = regexp.MustCompile
+var _ = fmt.Println
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"fmt"
+	"strings" // want `import "strings" belongs to group "strings" \(group 3\) but appears in group 1 \("fmt:os"\)`
+
+	// time is needed too
+	"time"
+
+	"os" // want `import "os" belongs to group "fmt:os" \(group 1\) but appears after group 2 \("time"\)`
+)
+
+func Nothing() {
+	fmt.Println(strings.HasPrefix("a", "b"), os.Args, time.Now())
+}
@@ -0,0 +1,14 @@
+package relaxed_order
+
+import (
+	"strings"
+
+	"time"
+
+	"fmt"
+	"sort" // want `import "sort" belongs to group "strings:sort" \(group 3\) but appears in group 1 \("fmt:os"\)`
+
+	"os" // want `import "os" starts another block of group "fmt:os" \(group 1\) instead of joining the block before it`
+)
+
+var _, _, _, _, _ = fmt.Println, os.Exit, time.Now, strings.Cut, sort.Ints
@@ -1,11 +1,11 @@
 package main
 
-import ( // want `File is not goimportgroups-ed`
+import (
 	"fmt"
 	"os"
-	"regexp"
-	"strings"
-	"time"
+	"regexp"  // want `import "regexp" belongs to group "regexp" \(group 4\) but appears in group 1 \("fmt:os"\)`
+	"strings" // want `import "strings" belongs to group "strings" \(group 3\) but appears in group 1 \("fmt:os"\)`
+	"time"    // want `import "time" belongs to group "time" \(group 2\) but appears in group 1 \("fmt:os"\)`
 )
 
 func Nothing() {
@@ -0,0 +1,11 @@
+package sorted
+
+import (
+	"os"
+	"fmt" // want `import "fmt" is not sorted: it goes before "os"`
+
+	"strings"
+	"time"
+)
+
+var _, _, _, _ = fmt.Println, os.Exit, time.Now, strings.Cut
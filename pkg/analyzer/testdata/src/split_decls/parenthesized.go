@@ -0,0 +1,13 @@
+package split_decls
+
+import (
+	"fmt"
+)
+
+//go:generate echo generated
+
+import "os" // want `import declaration is split by a comment from the imports declared at line 3`
+
+func Parenthesized() {
+	fmt.Println(os.Args)
+}
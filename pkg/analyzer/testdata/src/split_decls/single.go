@@ -0,0 +1,12 @@
+package split_decls
+
+import "fmt"
+
+// the strings package is used below
+import ( // want `import declaration is split by a comment from the imports declared at line 3`
+	"strings"
+)
+
+func Single() {
+	fmt.Println(strings.ToUpper(""))
+}
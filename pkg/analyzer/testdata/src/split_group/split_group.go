@@ -0,0 +1,12 @@
+package split_group
+
+import (
+	"fmt"
+
+	"os" // want `import "os" starts another block of group "fmt:os" \(group 1\) instead of joining the block before it`
+
+	"time"
+	"strings" // want `import "strings" belongs to group "strings" \(group 3\) but appears in group 2 \("time"\)`
+)
+
+var _, _, _, _ = fmt.Println, os.Exit, time.Now, strings.Cut
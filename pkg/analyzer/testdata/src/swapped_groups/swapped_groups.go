@@ -1,14 +1,14 @@
 package main
 
-import ( // want `File is not goimportgroups-ed`
+import (
 	"fmt"
 	"os"
 
 	"regexp"
 
-	"strings"
+	"strings" // want `import "strings" belongs to group "strings" \(group 3\) but appears after group 4 \("regexp"\)`
 
-	"time"
+	"time" // want `import "time" belongs to group "time" \(group 2\) but appears after group 4 \("regexp"\)`
 )
 
 func Nothing() {
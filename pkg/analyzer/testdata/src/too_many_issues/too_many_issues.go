@@ -0,0 +1,16 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"  // want `import "regexp" belongs to group "regexp" \(group 4\) but appears in group 1 \("fmt:os"\)`
+	"strings" // want `2 more issues in this file are not reported`
+	"time"
+)
+
+func Nothing() {
+	fmt.Println(strings.HasPrefix("a", "b"))
+	fmt.Println(os.Getenv("test"))
+	fmt.Println(time.Now().String())
+	fmt.Println(regexp.Regexp{})
+}
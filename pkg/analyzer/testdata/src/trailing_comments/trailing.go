@@ -0,0 +1,12 @@
+package main
+
+import (
+	"github.com/org/lib" // the library
+
+	"fmt" // printing
+	"os"
+	"strings" // for Join
+	"time"    // for Now
+)
+
+var _, _, _, _, _ = lib.X, fmt.Println, os.Exit, strings.Join, time.Now
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"errors" // want `import "errors" does not belong to any group`
+	"fmt"
+	"os"
+
+	"time"
+)
+
+func Nothing() {
+	fmt.Println(errors.New("test"))
+	fmt.Println(os.Getenv("test"))
+	fmt.Println(time.Now().String())
+}
@@ -0,0 +1,8 @@
+package yaml_config
+
+import (
+	"fmt"
+	"time" // want `import "time" belongs to group "time" \(group 2\) but appears in group 1 \("fmt"\)`
+)
+
+var _, _ = fmt.Println, time.Now
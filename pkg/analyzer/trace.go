@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TraceMatch returns the number, starting at 1, of the group importPath belongs to, or 0 if it matches none, along
+// with the trace of the evaluation of the patterns of the groups up to that one: a line per group, followed by a line
+// per subexpression of its pattern, indented below its expression, telling whether it matches.
+func (c *Checker) TraceMatch(importPath string) (int, string, error) {
+	var b strings.Builder
+	for i, pattern := range c.patterns {
+		e, err := c.matcher.expr(pattern)
+		if err != nil {
+			return 0, "", err
+		}
+
+		label := fmt.Sprintf("group %d", i+1)
+		if c.names[i] != pattern {
+			label += fmt.Sprintf(" %q", c.names[i])
+		}
+
+		fmt.Fprintf(&b, "%s:\n", label)
+		if traceExpr(&b, e, importPath, 1) {
+			return i + 1, b.String(), nil
+		}
+	}
+
+	return 0, b.String(), nil
+}
+
+// traceExpr writes to b a line telling whether e matches s, indented by depth, followed by the lines of its operands,
+// and returns whether e matches s.
+func traceExpr(b *strings.Builder, e expr, s string, depth int) bool {
+	matches := e.matches(s)
+	fmt.Fprintf(b, "%s%s: %s\n", strings.Repeat("  ", depth), formatExpr(e), describeMatch(matches))
+
+	switch e := e.(type) {
+	case notExpr:
+		traceExpr(b, e.x, s, depth+1)
+	case andExpr:
+		traceExpr(b, e.l, s, depth+1)
+		traceExpr(b, e.r, s, depth+1)
+	case orExpr:
+		traceExpr(b, e.l, s, depth+1)
+		traceExpr(b, e.r, s, depth+1)
+	}
+
+	return matches
+}
+
+// formatExpr returns e as written in a pattern, the operators normalized and parenthesized where their precedences
+// require it.
+func formatExpr(e expr) string {
+	switch e := e.(type) {
+	case regexExpr:
+		return e.pattern
+	case keywordExpr:
+		return e.keyword
+	case notExpr:
+		switch e.x.(type) {
+		case andExpr, orExpr:
+			return "!(" + formatExpr(e.x) + ")"
+		}
+
+		return "!" + formatExpr(e.x)
+	case andExpr:
+		return formatOperand(e.l) + " && " + formatOperand(e.r)
+	case orExpr:
+		return formatExpr(e.l) + " || " + formatExpr(e.r)
+	}
+
+	return fmt.Sprint(e)
+}
+
+// formatOperand returns the operand e of an and expression, parenthesized if it is an or expression.
+func formatOperand(e expr) string {
+	if _, ok := e.(orExpr); ok {
+		return "(" + formatExpr(e) + ")"
+	}
+
+	return formatExpr(e)
+}
+
+// describeMatch returns the words of a trace telling whether an expression matches.
+func describeMatch(matches bool) string {
+	if matches {
+		return "matches"
+	}
+
+	return "does not match"
+}
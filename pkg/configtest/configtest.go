@@ -0,0 +1,190 @@
+// Package configtest helps writing regression tests for goimportgroups configurations.
+//
+// A test points Run at a directory of sample Go files annotated the same way as for
+// golang.org/x/tools/go/analysis/analysistest: a `// want "regexp"` comment on a line expects an issue on that line
+// whose message matches the regexp. Files without annotations are expected to pass. A file with a sibling .golden
+// file, like sample.go.golden, is expected to be fixed into the content of the golden file.
+package configtest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+type expectation struct {
+	line int
+	re   *regexp.Regexp
+}
+
+// Run checks every .go file directly inside dir against cfg and reports through t any issue that is not expected, any
+// expectation no issue matches, and any fixed source that differs from its golden file.
+func Run(t testing.TB, cfg analyzer.Config, dir string) {
+	t.Helper()
+
+	c, err := analyzer.NewChecker(cfg)
+	if err != nil {
+		t.Fatalf("invalid configuration: %v", err)
+	}
+
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(filenames)
+
+	files := make([]analyzer.NamedSource, len(filenames))
+	for i, filename := range filenames {
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		files[i] = analyzer.NamedSource{Name: filename, Src: src}
+	}
+
+	for i, result := range c.CheckFiles(files) {
+		if result.Err != nil {
+			t.Errorf("%s: %v", result.Name, result.Err)
+			continue
+		}
+
+		expectations, err := parseExpectations(files[i])
+		if err != nil {
+			t.Errorf("%s: %v", result.Name, err)
+			continue
+		}
+
+		for _, iss := range result.Issues {
+			if !consume(&expectations, iss) {
+				t.Errorf("%s: unexpected issue: %s", iss.Pos, iss.Message)
+			}
+		}
+
+		for _, e := range expectations {
+			t.Errorf("%s:%d: no issue was reported matching %#q", result.Name, e.line, e.re)
+		}
+
+		checkGolden(t, c, files[i])
+	}
+}
+
+// checkGolden reports through t if the source of file, as fixed by c, differs from the golden file of file, if any.
+func checkGolden(t testing.TB, c *analyzer.Checker, file analyzer.NamedSource) {
+	t.Helper()
+
+	golden, err := os.ReadFile(file.Name + ".golden")
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+
+	if err != nil {
+		t.Errorf("%s: %v", file.Name, err)
+		return
+	}
+
+	fixed, _, err := c.FixBytes(file.Name, file.Src)
+	if err != nil {
+		t.Errorf("%s: %v", file.Name, err)
+		return
+	}
+
+	if !bytes.Equal(fixed, golden) {
+		t.Errorf("%s: the fixed source differs from %s.golden, got\n%s\nwant\n%s", file.Name, file.Name, fixed, golden)
+	}
+}
+
+// consume removes the first expectation matching iss and reports whether there was one.
+func consume(expectations *[]expectation, iss analyzer.Issue) bool {
+	for i, e := range *expectations {
+		if e.line == iss.Pos.Line && e.re.MatchString(iss.Message) {
+			*expectations = append((*expectations)[:i], (*expectations)[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseExpectations(file analyzer.NamedSource) ([]expectation, error) {
+	var expectations []expectation
+
+	fset := token.NewFileSet()
+	tokFile := fset.AddFile(file.Name, -1, len(file.Src))
+
+	var s scanner.Scanner
+	s.Init(tokFile, file.Src, nil, scanner.ScanComments)
+
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		if tok != token.COMMENT {
+			continue
+		}
+
+		text, ok := strings.CutPrefix(strings.TrimPrefix(lit, "//"), " want ")
+		if !ok {
+			continue
+		}
+
+		line := tokFile.Line(pos)
+
+		patterns, err := parsePatterns(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		for _, pattern := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+
+			expectations = append(expectations, expectation{line: line, re: re})
+		}
+	}
+
+	return expectations, nil
+}
+
+// parsePatterns splits text into the Go string literals it consists of.
+func parsePatterns(text string) ([]string, error) {
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	s.Init(fset.AddFile("", -1, len(text)), []byte(text), nil, 0)
+
+	var patterns []string
+	for {
+		_, tok, lit := s.Scan()
+		switch tok {
+		case token.EOF:
+			return patterns, nil
+		case token.STRING:
+			pattern, err := strconv.Unquote(lit)
+			if err != nil {
+				return nil, err
+			}
+
+			patterns = append(patterns, pattern)
+		case token.SEMICOLON: // inserted automatically at the end of the text
+		default:
+			return nil, fmt.Errorf("want expects string literals, found %s", tok)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package configtest_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+	"github.com/kmirzavaziri/goimportgroups/pkg/configtest"
+)
+
+// recorder records the errors reported through it instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRun(t *testing.T) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "[a-z/]+;github.com/.*"
+
+	configtest.Run(t, cfg, "testdata")
+}
+
+func TestRunFailures(t *testing.T) {
+	cfg := analyzer.DefaultConfig()
+	cfg.Groups = "[a-z/]+;github.com/.*"
+
+	r := &recorder{TB: t}
+	configtest.Run(r, cfg, filepath.Join("testdata", "failing"))
+
+	want := []string{
+		"unexpected.go:5:2: unexpected issue",
+		"unmet.go:4: no issue was reported matching",
+		"wrong.go: the fixed source differs from",
+	}
+	if len(r.errors) != len(want) {
+		t.Fatalf("expected %d errors, got %q", len(want), r.errors)
+	}
+
+	for i, prefix := range want {
+		if !strings.Contains(r.errors[i], prefix) {
+			t.Errorf("expected error %d to contain %q, got %q", i, prefix, r.errors[i])
+		}
+	}
+}
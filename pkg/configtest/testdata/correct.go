@@ -0,0 +1,10 @@
+package sample
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/acme/lib"
+)
+
+var _, _, _ = fmt.Println, os.Exit, lib.Name
@@ -0,0 +1,9 @@
+package sample
+
+import (
+	"fmt"
+	"github.com/acme/lib"
+	"os"
+)
+
+var _, _, _ = fmt.Println, os.Exit, lib.Name
@@ -0,0 +1,9 @@
+package sample
+
+import (
+	"fmt" // want `belongs to group`
+
+	"github.com/acme/lib"
+)
+
+var _, _ = fmt.Println, lib.Name
@@ -0,0 +1,9 @@
+package sample
+
+import (
+	"github.com/acme/lib"
+
+	"fmt" // want `import "fmt" belongs to group "\[a-z/\]\+" \(group 1\)`
+)
+
+var _, _ = fmt.Println, lib.Name
@@ -0,0 +1,9 @@
+package sample
+
+import (
+	"fmt"
+	"github.com/acme/lib" // want `import "github.com/acme/lib" belongs to group "github\.com/.*" \(group 2\)`
+	"os"
+)
+
+var _, _, _ = fmt.Println, os.Exit, lib.Name
@@ -0,0 +1,152 @@
+// Package golangci builds the goimportgroups analyzer from golangci-lint settings.
+//
+// New has the signature of the constructors of golangci-lint plugins. A module plugin registers it from a package of
+// its own:
+//
+//	func init() {
+//		register.Plugin("goimportgroups", func(settings any) (register.LinterPlugin, error) {
+//			return plugin{settings: settings}, nil
+//		})
+//	}
+//
+// with a plugin type whose BuildAnalyzers method returns golangci.New(settings), and whose GetLoadMode method returns
+// register.LoadModeTypesInfo for the redundant alias fixes to be checked against the type information.
+package golangci
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+)
+
+// Group is a group of the settings.
+type Group = analyzer.ConfigGroup
+
+// New returns the goimportgroups analyzer configured by settings, the settings of the linter in .golangci.yml. Their
+// keys are the names of the flags of the analyzer, lists setting repeatable flags like exclude once per item and maps
+// the pairs of flags like severities, except for groups, a list of groups with a pattern, an optional comment, an
+// optional name and an optional required:
+//
+//	linters-settings:
+//	  custom:
+//	    goimportgroups:
+//	      type: module
+//	      settings:
+//	        groups:
+//	          - pattern: std
+//	            name: stdlib
+//	          - pattern: .*
+//	        preview: 3
+//
+// Every call returns an analyzer of its own, so the settings of one call, or of a failed one, leave the others alone.
+func New(settings any) ([]*analysis.Analyzer, error) {
+	values, err := decodeSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	a := analyzer.NewAnalyzer()
+
+	if raw, ok := values["groups"]; ok {
+		delete(values, "groups")
+
+		var groups []Group
+		if err := redecode(raw, &groups); err != nil {
+			return nil, fmt.Errorf("%w: invalid groups setting: %w", analyzer.ErrConfigInvalid, err)
+		}
+
+		flags, err := analyzer.GroupFlags(groups)
+		if err != nil {
+			return nil, err
+		}
+
+		for name, value := range flags {
+			values[name] = value
+		}
+	}
+
+	for name, value := range values {
+		if a.Flags.Lookup(name) == nil {
+			return nil, fmt.Errorf("%w: unknown setting %s", analyzer.ErrConfigInvalid, name)
+		}
+
+		// a list sets a repeatable flag, like exclude, once per item, and a map a list of pairs, like severities
+		items, ok := value.([]any)
+		if pairs, isMap := value.(map[string]any); isMap {
+			items, ok = []any{joinPairs(pairs)}, true
+		}
+		if !ok {
+			items = []any{value}
+		}
+
+		for _, item := range items {
+			if err := a.Flags.Set(name, fmt.Sprint(item)); err != nil {
+				return nil, fmt.Errorf("%w: invalid setting %s: %w", analyzer.ErrConfigInvalid, name, err)
+			}
+		}
+	}
+
+	// check the settings right away rather than once the first package is analyzed, standing in for the module path
+	// of localmodule, which is only read from go.mod then
+	cfg := analyzer.OverrideConfig(analyzer.DefaultConfig(), &a.Flags)
+	if cfg.LocalModule == "" {
+		cfg.LocalModule = "example.com/module"
+	}
+
+	if _, err := analyzer.NewChecker(cfg); err != nil {
+		return nil, err
+	}
+
+	return []*analysis.Analyzer{a}, nil
+}
+
+// joinPairs returns the comma separated key=value pairs of m, sorted by key.
+func joinPairs(m map[string]any) string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", key, m[key])
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+// decodeSettings returns settings, as decoded by golangci-lint from its configuration, as a map of setting names to
+// values.
+func decodeSettings(settings any) (map[string]any, error) {
+	values := make(map[string]any)
+	if settings == nil {
+		return values, nil
+	}
+
+	if err := redecode(settings, &values); err != nil {
+		return nil, fmt.Errorf("%w: invalid settings: %w", analyzer.ErrConfigInvalid, err)
+	}
+
+	return values, nil
+}
+
+// redecode decodes v into target through JSON, whatever the types of maps and values golangci-lint decoded it into.
+func redecode(v any, target any) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(&buf)
+	dec.DisallowUnknownFields()
+
+	return dec.Decode(target)
+}
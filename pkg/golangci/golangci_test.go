@@ -0,0 +1,87 @@
+package golangci_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kmirzavaziri/goimportgroups/pkg/analyzer"
+	"github.com/kmirzavaziri/goimportgroups/pkg/golangci"
+)
+
+func TestNew(t *testing.T) {
+	settings := map[string]any{
+		"groups": []any{
+			map[string]any{"pattern": "std", "name": "stdlib", "required": true},
+			map[string]any{"pattern": ".*", "comment": "why"},
+		},
+		"preview":            3,
+		"collapse-identical": true,
+		"exclude":            []any{"vendor/.*", ".*_test\\.go"},
+	}
+
+	analyzers, err := golangci.New(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(analyzers) != 1 {
+		t.Fatalf("expected a single analyzer, got %d", len(analyzers))
+	}
+
+	flags := analyzers[0].Flags
+
+	for name, want := range map[string]string{
+		"groups":             "std;.*",
+		"comments":           ";why",
+		"group-names":        "stdlib",
+		"required-groups":    "1",
+		"preview":            "3",
+		"collapse-identical": "true",
+		"exclude":            `vendor/.*;.*_test\.go`,
+	} {
+		if got := flags.Lookup(name).Value.String(); got != want {
+			t.Errorf("expected %s to be %q, got %q", name, want, got)
+		}
+	}
+
+	// the settings of another call, failed or not, leave the analyzer alone
+	if _, err := golangci.New(map[string]any{"exclude": "gen/.*", "preview": "many"}); err == nil {
+		t.Fatal("expected an error for an invalid preview")
+	}
+
+	again, err := golangci.New(map[string]any{
+		"exclude":    []any{"gen/.*"},
+		"severities": map[string]any{"unsorted-import": "warning", "group-order": "error"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"exclude":    "gen/.*",
+		"preview":    flags.Lookup("preview").DefValue,
+		"severities": "group-order=error,unsorted-import=warning",
+	} {
+		if got := again[0].Flags.Lookup(name).Value.String(); got != want {
+			t.Errorf("expected %s of the second analyzer to be %q, got %q", name, want, got)
+		}
+	}
+
+	if got := flags.Lookup("exclude").Value.String(); got != `vendor/.*;.*_test\.go` {
+		t.Errorf("expected the exclude of the first analyzer to be left alone, got %q", got)
+	}
+
+	for _, settings := range []map[string]any{
+		{"unknown": true},
+		{"preview": "many"},
+		{"groups": []any{map[string]any{"comment": "why"}}},
+		{"groups": "std;.*"},
+		{"groups": []any{map[string]any{"pattern": "std && (fmt"}}},
+		{"sort": "random"},
+	} {
+		_, err := golangci.New(settings)
+		if !errors.Is(err, analyzer.ErrConfigInvalid) {
+			t.Errorf("expected an invalid config error for %v, got %v", settings, err)
+		}
+	}
+}